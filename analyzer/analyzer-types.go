@@ -0,0 +1,180 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"go/types"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+type cArgKind uint16
+
+const (
+	gArgBool cArgKind = 1 << iota
+	gArgInt
+	gArgFloat
+	gArgComplex
+	gArgString
+	gArgPointer
+	gArgError
+
+	gArgAny      cArgKind = 0xffff
+	gArgNumber            = gArgInt | gArgFloat | gArgComplex
+	gArgHexable           = gArgNumber | gArgString | gArgPointer
+	gArgQuotable          = gArgString | gArgInt
+)
+
+// gVerbKinds are the kinds of arguments accepted by each of the fmt verbs,
+// see https://pkg.go.dev/fmt#hdr-Printing
+var gVerbKinds = map[fmtstr.Verb]cArgKind{
+	"b": gArgNumber | gArgPointer,
+	"c": gArgInt,
+	"d": gArgInt | gArgPointer,
+	"e": gArgFloat | gArgComplex,
+	"E": gArgFloat | gArgComplex,
+	"f": gArgFloat | gArgComplex,
+	"F": gArgFloat | gArgComplex,
+	"g": gArgFloat | gArgComplex,
+	"G": gArgFloat | gArgComplex,
+	"o": gArgInt | gArgPointer,
+	"O": gArgInt | gArgPointer,
+	"p": gArgPointer,
+	"q": gArgQuotable,
+	"s": gArgString,
+	"t": gArgBool,
+	"T": gArgAny,
+	"U": gArgInt,
+	"v": gArgAny,
+	"w": gArgError,
+	"x": gArgHexable,
+	"X": gArgHexable,
+}
+
+// matchVerb returns true if an argument of the given type is valid for the
+// fmt verb given. The %w verb matches only error and interface types. Types
+// implementing fmt.Formatter and interface types match all other verbs,
+// types implementing error or fmt.Stringer match the textual verbs and the
+// elements of arrays, slices, maps, structs and pointers to these are
+// matched individually as fmt does
+func matchVerb(verb fmtstr.Verb, typ types.Type) (match bool) {
+	kinds, present := gVerbKinds[verb]
+	if !present {
+		return
+	}
+	match = matchKinds(kinds, typ, true, make(map[types.Type]struct{}))
+	return
+}
+
+func matchKinds(kinds cArgKind, typ types.Type, top bool, seen map[types.Type]struct{}) (match bool) {
+	if kinds == gArgAny {
+		return true
+	} else if kinds == gArgError {
+		// fmt.Errorf only wraps error values
+		_, isInterface := typ.Underlying().(*types.Interface)
+		return isInterface || hasMethod(typ, "Error")
+	} else if _, cyclic := seen[typ]; cyclic {
+		return true
+	}
+	seen[typ] = struct{}{}
+	defer delete(seen, typ)
+
+	if hasMethod(typ, "Format") {
+		return true
+	} else if kinds&gArgString != 0 && (hasMethod(typ, "Error") || hasMethod(typ, "String")) {
+		return true
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Interface:
+		return true
+
+	case *types.Basic:
+		switch {
+		case t.Kind() == types.UnsafePointer:
+			return kinds&gArgPointer != 0
+		case t.Info()&types.IsBoolean != 0:
+			return kinds&gArgBool != 0
+		case t.Info()&types.IsInteger != 0:
+			return kinds&gArgInt != 0
+		case t.Info()&types.IsFloat != 0:
+			return kinds&gArgFloat != 0
+		case t.Info()&types.IsComplex != 0:
+			return kinds&gArgComplex != 0
+		case t.Info()&types.IsString != 0:
+			return kinds&gArgString != 0
+		}
+		return false
+
+	case *types.Slice:
+		if kinds == gArgPointer {
+			// only %p prints slices as pointers
+			return true
+		}
+		if basic, ok := t.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte && kinds&gArgString != 0 {
+			// []byte is printed as a string
+			return true
+		}
+		return matchKinds(kinds, t.Elem(), false, seen)
+
+	case *types.Array:
+		if basic, ok := t.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte && kinds&gArgString != 0 {
+			return true
+		}
+		return matchKinds(kinds, t.Elem(), false, seen)
+
+	case *types.Map:
+		if kinds == gArgPointer {
+			// only %p prints maps as pointers
+			return true
+		}
+		return matchKinds(kinds, t.Key(), false, seen) && matchKinds(kinds, t.Elem(), false, seen)
+
+	case *types.Struct:
+		for idx := 0; idx < t.NumFields(); idx++ {
+			if !matchKinds(kinds, t.Field(idx).Type(), false, seen) {
+				return false
+			}
+		}
+		return true
+
+	case *types.Pointer:
+		if kinds&gArgPointer != 0 {
+			return true
+		}
+		if top {
+			// fmt prints the elements of top-level pointers to arrays,
+			// slices, structs and maps
+			switch t.Elem().Underlying().(type) {
+			case *types.Array, *types.Slice, *types.Struct, *types.Map:
+				return matchKinds(kinds, t.Elem(), false, seen)
+			}
+		}
+		return false
+
+	case *types.Chan, *types.Signature:
+		return kinds&gArgPointer != 0
+	}
+
+	return false
+}
+
+// hasMethod returns true if the type given, or a pointer to it, has a method
+// with the given name
+func hasMethod(typ types.Type, name string) (present bool) {
+	obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, name)
+	_, present = obj.(*types.Func)
+	return
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// gSinkFuncs are the standard library printf-like functions which wrappers
+// are detected for, in addition to the Funcs
+var gSinkFuncs = map[string]int{
+	"fmt.Errorf":        0,
+	"fmt.Fprintf":       1,
+	"fmt.Printf":        0,
+	"fmt.Sprintf":       0,
+	"log.Fatalf":        0,
+	"log.Panicf":        0,
+	"log.Printf":        0,
+	"log.Logger.Fatalf": 0,
+	"log.Logger.Panicf": 0,
+	"log.Logger.Printf": 0,
+}
+
+// WrapperFact is the analysis fact exported for printf-like wrappers, which
+// are the functions with a `format string, args ...interface{}` pair of
+// final parameters that are passed unchanged to a printf-like function (or
+// another wrapper)
+type WrapperFact struct {
+	// Format is the index of the format argument
+	Format int
+}
+
+func (f *WrapperFact) AFact() {}
+
+func (f *WrapperFact) String() string {
+	return fmt.Sprintf("printf wrapper (format %d)", f.Format)
+}
+
+// cWrapper is a function which may be a printf-like wrapper
+type cWrapper struct {
+	fn     *types.Func
+	body   *ast.BlockStmt
+	format *types.Var
+	args   *types.Var
+	index  int
+}
+
+// findWrappers returns the printf-like wrappers declared in the package,
+// mapped to the index of their format argument. Wrappers of wrappers within
+// the same package are found by repeating the search until no more
+// wrappers are found and wrappers in other packages are found by their
+// WrapperFact
+func findWrappers(pass *analysis.Pass) (wrappers map[*types.Func]int) {
+	wrappers = make(map[*types.Func]int)
+
+	var candidates []*cWrapper
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+				if fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+					if candidate := newWrapper(fn, fd.Body); candidate != nil {
+						candidates = append(candidates, candidate)
+					}
+				}
+			}
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, candidate := range candidates {
+			if _, present := wrappers[candidate.fn]; !present && candidate.forwards(pass, wrappers) {
+				wrappers[candidate.fn] = candidate.index
+				changed = true
+			}
+		}
+	}
+	return
+}
+
+// newWrapper returns a cWrapper if the signature of the function given ends
+// with a string parameter and a variadic empty interface parameter
+func newWrapper(fn *types.Func, body *ast.BlockStmt) (w *cWrapper) {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() || sig.Params().Len() < 2 {
+		return
+	}
+	params := sig.Params()
+	format, args := params.At(params.Len()-2), params.At(params.Len()-1)
+
+	if basic, ok := format.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+		return
+	} else if slice, ok := args.Type().(*types.Slice); !ok {
+		return
+	} else if iface, ok := slice.Elem().Underlying().(*types.Interface); !ok || !iface.Empty() {
+		return
+	}
+
+	w = &cWrapper{
+		fn:     fn,
+		body:   body,
+		format: format,
+		args:   args,
+		index:  params.Len() - 2,
+	}
+	return
+}
+
+// forwards returns true if the function body calls a printf-like function,
+// or wrapper, with the format and args parameters unchanged
+func (w *cWrapper) forwards(pass *analysis.Pass, wrappers map[*types.Func]int) (found bool) {
+	ast.Inspect(w.body, func(node ast.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := node.(*ast.CallExpr)
+		if !ok || !call.Ellipsis.IsValid() {
+			return true
+		}
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok {
+			return true
+		}
+
+		index, present := wrappers[fn]
+		if !present {
+			name := FuncName(fn)
+			if index, present = formatIndex(pass, fn, name); !present {
+				index, present = lookupFunc(gSinkFuncs, name)
+			}
+		}
+
+		if present && index >= 0 && len(call.Args) == index+2 {
+			found = w.uses(pass, call.Args[index], w.format) && w.uses(pass, call.Args[index+1], w.args)
+		}
+		return !found
+	})
+	return
+}
+
+// uses returns true if the expression given is the parameter given
+func (w *cWrapper) uses(pass *analysis.Pass, expr ast.Expr, param *types.Var) (used bool) {
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+		used = pass.TypesInfo.Uses[ident] == param
+	}
+	return
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analyzer provides a go/analysis Analyzer which checks the calls to
+// printf-like translation functions, using fmtstr.Decompose to parse the
+// format strings and go/types to check the argument count and types.
+//
+// Printf-like wrappers, which pass their `format string, args ...any`
+// parameters unchanged to fmt, log or any of the Funcs, are detected
+// automatically and exported as WrapperFact analysis facts so that calls to
+// wrappers (and wrappers of wrappers) are checked across packages. The
+// Result of the Analyzer contains the wrappers and the decomposed format
+// strings of all of the checked calls.
+//
+// The Analyzer can be run with singlechecker or multichecker, for example:
+//
+//	func main() {
+//	    singlechecker.Main(analyzer.Analyzer)
+//	}
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/go-corelibs/fmtstr"
+	"github.com/go-corelibs/fmtstr/extract"
+)
+
+// DefaultFuncs are the printf-like functions checked by default, mapped to
+// the index of their format argument, see Funcs
+var DefaultFuncs = map[string]int{
+	"_":                       0,
+	"message.Printer.Fprintf": 1,
+	"message.Printer.Printf":  0,
+	"message.Printer.Sprintf": 0,
+}
+
+// Analyzer checks the format strings and arguments of the calls to the
+// configured printf-like functions
+var Analyzer = &analysis.Analyzer{
+	Name:       "fmtstr",
+	Doc:        "check the format strings and arguments of printf-like translation calls",
+	URL:        "https://pkg.go.dev/github.com/go-corelibs/fmtstr/analyzer",
+	Run:        run,
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes:  []analysis.Fact{new(WrapperFact)},
+	ResultType: reflect.TypeOf((*Result)(nil)),
+}
+
+// Result is the result of the Analyzer for a single package
+type Result struct {
+	// Wrappers are the printf-like wrappers declared in the package, mapped
+	// to the index of their format argument, see WrapperFact
+	Wrappers map[*types.Func]int
+	// Messages are the decomposed constant format strings of all of the
+	// printf-like calls in the package, including calls to wrappers
+	Messages []*extract.Message
+}
+
+// Funcs are the printf-like functions checked by the Analyzer, mapped to the
+// index of their format argument, where any arguments following the format
+// are the format string arguments and a negative index disables checking.
+// The names are matched against the fully qualified function names (ie:
+// `golang.org/x/text/message.Printer.Sprintf`), either exactly or as a
+// suffix following a dot or slash, so that `Printer.Sprintf` matches calls
+// to the Sprintf method of any type named Printer. Funcs can be set with the
+// `-funcs` flag, which is a comma-separated list of names with optional
+// format indexes, ie: `-funcs=_,Printer.Sprintf,Printer.Fprintf:1`
+var Funcs = copyFuncs(DefaultFuncs)
+
+func init() {
+	Analyzer.Flags.Var(cFuncsFlag{}, "funcs", "comma-separated list of printf-like function names, with optional :index of the format argument")
+}
+
+type cFuncsFlag struct{}
+
+func (f cFuncsFlag) String() string {
+	var names []string
+	for name, index := range Funcs {
+		names = append(names, name+":"+strconv.Itoa(index))
+	}
+	return strings.Join(names, ",")
+}
+
+func (f cFuncsFlag) Set(value string) (err error) {
+	funcs := make(map[string]int)
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		index := 0
+		if idx := strings.LastIndexByte(name, ':'); idx > -1 {
+			if index, err = strconv.Atoi(name[idx+1:]); err != nil {
+				err = fmt.Errorf("invalid format index: %q", name)
+				return
+			}
+			name = name[:idx]
+		}
+		funcs[name] = index
+	}
+	Funcs = funcs
+	return
+}
+
+func copyFuncs(funcs map[string]int) (copied map[string]int) {
+	copied = make(map[string]int)
+	for name, index := range funcs {
+		copied[name] = index
+	}
+	return
+}
+
+func run(pass *analysis.Pass) (result interface{}, err error) {
+	found := &Result{Wrappers: findWrappers(pass)}
+	for fn, index := range found.Wrappers {
+		pass.ExportObjectFact(fn, &WrapperFact{Format: index})
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok {
+			return
+		}
+		name := FuncName(fn)
+		if index, present := formatIndex(pass, fn, name); present && index >= 0 {
+			if message := checkCall(pass, call, name, index); message != nil {
+				found.Messages = append(found.Messages, message)
+			}
+		}
+	})
+
+	result = found
+	return
+}
+
+// formatIndex returns the format argument index of the function given, if
+// it is one of the Funcs or is a printf-like wrapper
+func formatIndex(pass *analysis.Pass, fn *types.Func, name string) (index int, found bool) {
+	if index, found = lookupFunc(Funcs, name); found {
+		return
+	}
+	var fact WrapperFact
+	if found = pass.ImportObjectFact(fn, &fact); found {
+		index = fact.Format
+	}
+	return
+}
+
+// FuncName returns the fully qualified name of the function given, for
+// example: `fmt.Sprintf` or `golang.org/x/text/message.Printer.Sprintf`
+func FuncName(fn *types.Func) (name string) {
+	name = fn.Name()
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if named, ok := recv.(*types.Named); ok {
+			name = named.Origin().Obj().Name() + "." + name
+		}
+	}
+	if pkg := fn.Pkg(); pkg != nil {
+		name = pkg.Path() + "." + name
+	}
+	return
+}
+
+// lookupFunc returns the format index of the fully qualified function name
+// given, matching the names in funcs exactly or as suffixes
+func lookupFunc(funcs map[string]int, name string) (index int, found bool) {
+	if index, found = funcs[name]; found {
+		return
+	}
+	// the longest matching suffix wins
+	var longest string
+	for key, value := range funcs {
+		if strings.HasSuffix(name, "."+key) || strings.HasSuffix(name, "/"+key) {
+			if !found || len(key) > len(longest) {
+				index, found, longest = value, true, key
+			}
+		}
+	}
+	return
+}
+
+// checkCall checks the format string and arguments of the call given and
+// returns the Message of the format string if it is a valid constant
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, name string, index int) (message *extract.Message) {
+	short := name[strings.LastIndexByte(name, '/')+1:]
+	if index >= len(call.Args) {
+		pass.ReportRangef(call, "%s call is missing the format argument", short)
+		return
+	}
+
+	value := pass.TypesInfo.Types[call.Args[index]].Value
+	if value == nil || value.Kind() != constant.String {
+		// only constant format strings are checked
+		return
+	}
+	format := constant.StringVal(value)
+
+	args := call.Args[index+1:]
+	var argv []string
+	for _, arg := range args {
+		argv = append(argv, types.ExprString(arg))
+	}
+
+	decomposed, err := fmtstr.NewMessage(format, argv...)
+	if err != nil {
+		pass.ReportRangef(call.Args[index], "%s format %q is invalid: %v", short, format, err)
+		return
+	}
+	message = &extract.Message{
+		Message:  decomposed,
+		Func:     name,
+		Position: pass.Fset.Position(call.Pos()),
+	}
+
+	if call.Ellipsis.IsValid() {
+		// the arguments are not known
+		return
+	}
+
+	used := make(map[int]struct{})
+	explicit := false
+	for _, variable := range decomposed.Variables {
+		used[variable.Pos] = struct{}{}
+		if strings.Contains(variable.Source, "[") {
+			explicit = true
+		}
+		if variable.Pos > len(args) {
+			pass.ReportRangef(call, "%s format %s reads arg #%d, but call has %d args", short, variable.Source, variable.Pos, len(args))
+			continue
+		}
+		arg := args[variable.Pos-1]
+		if typ := pass.TypesInfo.TypeOf(arg); typ != nil && !matchVerb(variable.Verb, typ) {
+			pass.ReportRangef(arg, "%s format %s has arg %s of wrong type %s", short, variable.Source, types.ExprString(arg), typ)
+		}
+	}
+
+	if explicit {
+		// as with go vet, arguments may be deliberately skipped when the
+		// format has explicit argument indexes
+		return
+	}
+	for idx, arg := range args {
+		if _, present := used[idx+1]; !present {
+			pass.ReportRangef(arg, "%s call has arg %s which is not used by the format %q", short, types.ExprString(arg), format)
+		}
+	}
+	return
+}
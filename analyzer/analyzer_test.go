@@ -0,0 +1,56 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	Convey("Funcs flag", t, func() {
+		defer func() { Funcs = copyFuncs(DefaultFuncs) }()
+
+		So(Analyzer.Flags.Set("funcs", "_, Printer.Sprintf ,Printer.Fprintf:1,"), ShouldEqual, nil)
+		So(Funcs, ShouldResemble, map[string]int{"_": 0, "Printer.Sprintf": 0, "Printer.Fprintf": 1})
+		So(Analyzer.Flags.Lookup("funcs").Value.String(), ShouldNotEqual, "")
+		So(Analyzer.Flags.Set("funcs", "Printer.Fprintf:one"), ShouldNotEqual, nil)
+
+		index, found := lookupFunc(map[string]int{"Sprintf": 0, "Printer.Sprintf": 1}, "example.com/a.Printer.Sprintf")
+		So(found, ShouldBeTrue)
+		So(index, ShouldEqual, 1)
+		_, found = lookupFunc(Funcs, "example.com/a.Printer.Fprintln")
+		So(found, ShouldBeFalse)
+	})
+
+	Convey("Analyzer", t, func() {
+		defer func() { Funcs = copyFuncs(DefaultFuncs) }()
+		So(Analyzer.Flags.Set("funcs", "tr,Printer.Sprintf,Printer.Fprintf:1"), ShouldEqual, nil)
+		results := analysistest.Run(t, analysistest.TestData(), Analyzer, "a", "w", "b")
+		So(len(results), ShouldEqual, 3)
+
+		found := results[2].Result.(*Result)
+		So(len(found.Wrappers), ShouldEqual, 0)
+		So(len(found.Messages), ShouldEqual, 7)
+		So(found.Messages[0].Func, ShouldEqual, "w.Logger.Infof")
+		So(found.Messages[0].Labelled, ShouldEqual, "{cart} has {3} items")
+		So(found.Messages[0].Position.Line, ShouldEqual, 6)
+
+		found = results[1].Result.(*Result)
+		So(len(found.Wrappers), ShouldEqual, 6)
+	})
+}
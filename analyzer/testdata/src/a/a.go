@@ -0,0 +1,63 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Printer struct{}
+
+func (p *Printer) Sprintf(format string, args ...interface{}) string { // want Sprintf:"printf wrapper \\(format 0\\)"
+	return fmt.Sprintf(format, args...)
+}
+
+func (p *Printer) Fprintf(w io.Writer, format string, args ...interface{}) (int, error) { // want Fprintf:"printf wrapper \\(format 1\\)"
+	return fmt.Fprintf(w, format, args...)
+}
+
+func tr(format string, args ...interface{}) string { // want tr:"printf wrapper \\(format 0\\)"
+	return fmt.Sprintf(format, args...)
+}
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+type point struct{ X, Y int }
+
+const greeting = "Hello %s, you have %d messages"
+
+func calls(p *Printer, w io.Writer, args []interface{}) {
+	var n name
+	var count int
+	var ratio float64
+	var items []string
+	var err = errors.New("failed")
+
+	p.Sprintf(greeting, "World", 3)
+	p.Sprintf(greeting, n, count)
+	p.Sprintf("%s failed: %v", "task", err)
+	p.Sprintf("%[2]d items for %[1]s", "me", 4)
+	p.Sprintf("%[2]d items", "skipped", 4)
+	p.Sprintf("%x %X %q %c %U", "hex", []byte("x"), 'r', 'c', count)
+	p.Sprintf("%.2f%% done", ratio)
+	p.Sprintf("%d points: %v", len(items), &point{})
+	p.Sprintf("%d", &point{})
+	p.Sprintf("%s", items)
+	p.Sprintf("%s %p", err, &count)
+	p.Sprintf(greeting, args...)
+	p.Sprintf("not constant: " + string(n))
+
+	p.Sprintf(greeting, 3, "World")    // want `Printer.Sprintf format %s has arg 3 of wrong type int` `Printer.Sprintf format %d has arg "World" of wrong type string`
+	p.Sprintf("%d items", ratio)       // want `Printer.Sprintf format %d has arg ratio of wrong type float64`
+	p.Sprintf("%t %s", count, &count)  // want `format %t has arg count of wrong type int` `format %s has arg &count of wrong type \*int`
+	p.Sprintf("%s and %s", "one")      // want `Printer.Sprintf format %s reads arg #2, but call has 1 args`
+	p.Sprintf("%s", "one", "two")      // want `Printer.Sprintf call has arg "two" which is not used by the format "%s"`
+	p.Sprintf("%[1]d %[1]s", count)    // want `Printer.Sprintf format "%\[1\]d %\[1\]s" is invalid: conflicting substitution types`
+	p.Sprintf("%f", &point{1, 2})      // want `wrong type \*a.point`
+	p.Sprintf("%d", []string{"x"})     // want `wrong type \[\]string`
+	p.Fprintf(w, "%s has %d", n, "no") // want `Printer.Fprintf format %d has arg "no" of wrong type string`
+	tr("%d apples", "two")             // want `a.tr format %d has arg "two" of wrong type string`
+	fmt.Sprintf("%d", "not checked")
+}
@@ -0,0 +1,15 @@
+package b
+
+import "w"
+
+func calls(l *w.Logger, err error) {
+	l.Infof("%s has %d items", "cart", 3)
+	l.Logf("%d items", "three")        // want `w.Logger.Logf format %d has arg "three" of wrong type string`
+	l.Infof("%s has %d items", 3)      // want `w.Logger.Infof format %d reads arg #2, but call has 1 args` `format %s has arg 3 of wrong type int`
+	_ = w.Errorf(404, "%s missing", 1) // want `w.Errorf format %s has arg 1 of wrong type int`
+	_ = w.Wrapf("read %s: %w", "file", err)
+	_ = w.Wrapf("read: %w", "file") // want `w.Wrapf format %w has arg "file" of wrong type string`
+	_ = w.Sprintf("%t", true)
+	_ = w.Prefixed("%d", "not checked")
+	_ = w.Partial("%d", "not checked")
+}
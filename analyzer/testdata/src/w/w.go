@@ -0,0 +1,47 @@
+package w
+
+import (
+	"fmt"
+	"log"
+)
+
+type Logger struct {
+	*log.Logger
+}
+
+func (l *Logger) Logf(format string, args ...interface{}) { // want Logf:"printf wrapper \\(format 0\\)"
+	l.Logger.Printf(format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) { // want Infof:"printf wrapper \\(format 0\\)"
+	l.Logf(format, args...)
+}
+
+func Errorf(code int, format string, args ...any) error { // want Errorf:"printf wrapper \\(format 1\\)"
+	return fmt.Errorf("%d: %s", code, Sprintf(format, args...))
+}
+
+func Wrapf(format string, args ...any) error { // want Wrapf:"printf wrapper \\(format 0\\)"
+	return fmt.Errorf(format, args...)
+}
+
+func Sprintf(format string, args ...any) string { // want Sprintf:"printf wrapper \\(format 0\\)"
+	return fmt.Sprintf((format), args...)
+}
+
+func Prefixed(format string, args ...interface{}) string {
+	return fmt.Sprintf("prefix: "+format, args...)
+}
+
+func Partial(format string, args ...interface{}) string {
+	return fmt.Sprintf(format, args[1:]...)
+}
+
+func Strings(format string, args ...string) string {
+	return fmt.Sprint(format, args)
+}
+
+func Deferred(format string, args ...interface{}) { // want Deferred:"printf wrapper \\(format 0\\)"
+	defer func() { fmt.Sprintf(format, args...) }()
+	_ = Sprintf
+}
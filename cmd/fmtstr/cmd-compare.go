@@ -0,0 +1,52 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func init() {
+	register(&cCommand{
+		name:    "compare",
+		summary: "check that a translation is compatible with a source format",
+		usage:   "[-argv a,b,...] <source> <translation>",
+		setup: func(flags *flag.FlagSet) {
+			flags.String("argv", "", "comma-separated argv list used to label the variables")
+		},
+		run: runCompare,
+	})
+}
+
+func runCompare(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) != 2 {
+		flags.Usage()
+		return gExitInvalid
+	}
+
+	source, err := fmtstr.NewMessage(args[0], splitArgv(flagString(flags, "argv"))...)
+	if err != nil {
+		return failf(stderr, gExitInvalid, "source: %v", err)
+	}
+	if _, err = source.Check(args[1]); err != nil {
+		return failf(stderr, gExitFailed, "%v", err)
+	}
+	fmt.Fprintln(stdout, "ok")
+	return gExitOK
+}
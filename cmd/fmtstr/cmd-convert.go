@@ -0,0 +1,62 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func init() {
+	register(&cCommand{
+		name:    "convert",
+		summary: "convert a format string between dialects",
+		usage:   "[-from name] -to name <format> [argv...]",
+		setup: func(flags *flag.FlagSet) {
+			flags.String("from", fmtstr.GoDialect.Name(), "source dialect")
+			flags.String("to", "", "target dialect")
+		},
+		run: runConvert,
+	})
+}
+
+func runConvert(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) == 0 || flagString(flags, "to") == "" {
+		flags.Usage()
+		return gExitInvalid
+	}
+
+	from, err := lookupDialect(flagString(flags, "from"))
+	if err != nil {
+		return failf(stderr, gExitInvalid, "%v", err)
+	}
+	to, err := lookupDialect(flagString(flags, "to"))
+	if err != nil {
+		return failf(stderr, gExitInvalid, "%v", err)
+	}
+
+	converted, err := fmtstr.Convert(args[0], from, to, args[1:]...)
+	if errors.Is(err, fmtstr.ErrNoEquivalent) {
+		return failf(stderr, gExitFailed, "%v", err)
+	} else if err != nil {
+		return failf(stderr, gExitInvalid, "%v", err)
+	}
+	fmt.Fprintln(stdout, converted)
+	return gExitOK
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func init() {
+	register(&cCommand{
+		name:    "decompose",
+		summary: "print the replaced, labelled and variables of a format string",
+		usage:   "[-json] [-dialect name] <format> [argv...]",
+		setup: func(flags *flag.FlagSet) {
+			flags.Bool("json", false, "print the results as JSON")
+			flags.String("dialect", fmtstr.GoDialect.Name(), "format string dialect")
+		},
+		run: runDecompose,
+	})
+}
+
+func runDecompose(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) == 0 {
+		flags.Usage()
+		return gExitInvalid
+	}
+
+	dialect, err := lookupDialect(flagString(flags, "dialect"))
+	if err != nil {
+		return failf(stderr, gExitInvalid, "%v", err)
+	}
+
+	replaced, labelled, variables, err := fmtstr.DecomposeWith(dialect, args[0], args[1:]...)
+	if err != nil {
+		return failf(stderr, gExitInvalid, "%v", err)
+	}
+
+	if flagBool(flags, "json") {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(map[string]interface{}{
+			"replaced":  replaced,
+			"labelled":  labelled,
+			"variables": variables,
+		}); err != nil {
+			return failf(stderr, gExitFailed, "%v", err)
+		}
+		return gExitOK
+	}
+
+	fmt.Fprintf(stdout, "replaced: %s\n", replaced)
+	fmt.Fprintf(stdout, "labelled: %s\n", labelled)
+	fmt.Fprintf(stdout, "variables:\n")
+	for _, variable := range variables {
+		fmt.Fprintf(stdout, "  %-4s %-8s %-6s {%s}\n", strconv.Itoa(variable.Pos), variable.Source, variable.Type, variable.Label)
+	}
+	return gExitOK
+}
+
+// lookupDialect returns the named Dialect or an error listing the Dialects
+func lookupDialect(name string) (dialect fmtstr.Dialect, err error) {
+	if dialect = fmtstr.LookupDialect(name); dialect == nil {
+		var names []string
+		for _, d := range fmtstr.Dialects() {
+			names = append(names, d.Name())
+		}
+		err = fmt.Errorf("unknown dialect %q, expected one of: %v", name, names)
+	}
+	return
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-corelibs/fmtstr"
+	"github.com/go-corelibs/fmtstr/generate"
+)
+
+func init() {
+	register(&cCommand{
+		name:    "generate",
+		summary: "generate typed message functions from catalog source messages",
+		usage:   "[-lang go|ts] [-package name] [-o file] [-test file] <file> [file...]",
+		setup: func(flags *flag.FlagSet) {
+			flags.String("lang", "go", "the language of the generated code: go or ts (TypeScript)")
+			flags.String("package", generate.DefaultGoPackage, "the name of the generated Go package, not used by ts")
+			flags.String("o", "", "write the generated code to the named file instead of stdout")
+			flags.String("test", "", "also write a Go test of the generated code to the named file, not used by ts")
+		},
+		run: runGenerate,
+	})
+}
+
+func runGenerate(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) == 0 {
+		flags.Usage()
+		return gExitInvalid
+	}
+
+	var entries []*generate.Entry
+	for _, path := range args {
+		catalog, err := readCatalog(path)
+		if err == nil {
+			var found []*generate.Entry
+			if found, err = catalogEntries(catalog); err == nil {
+				entries = append(entries, found...)
+			}
+		}
+		if err != nil {
+			return failf(stderr, gExitInvalid, "%s: %v", path, err)
+		}
+	}
+
+	var source, test []byte
+	var err error
+	switch lang := flagString(flags, "lang"); lang {
+	case "go":
+		g := generate.NewGoGenerator(flagString(flags, "package"))
+		if source, err = g.Generate(entries...); err == nil && flagString(flags, "test") != "" {
+			test, err = g.GenerateTest(entries...)
+		}
+	case "ts":
+		if flagString(flags, "test") != "" {
+			return failf(stderr, gExitInvalid, "-test is only supported by -lang go")
+		}
+		source, err = generate.NewTypeScriptGenerator().Generate(entries...)
+	default:
+		return failf(stderr, gExitInvalid, "unknown language %q, expected one of: [go ts]", lang)
+	}
+	if err != nil {
+		return failf(stderr, gExitInvalid, "%v", err)
+	}
+
+	if path := flagString(flags, "test"); path != "" {
+		if err = os.WriteFile(path, test, 0644); err != nil {
+			return failf(stderr, gExitFailed, "%v", err)
+		}
+	}
+
+	if path := flagString(flags, "o"); path != "" {
+		if err = os.WriteFile(path, source, 0644); err != nil {
+			return failf(stderr, gExitFailed, "%v", err)
+		}
+		return gExitOK
+	}
+	if _, err = stdout.Write(source); err != nil {
+		return failf(stderr, gExitFailed, "%v", err)
+	}
+	return gExitOK
+}
+
+// catalogEntries returns a generate.Entry for each of the source messages of
+// the catalog given, see readCatalog. PO entries are named by their context,
+// ARB and Chrome messages by their keys and gotext messages are named by
+// generate.EntryName
+func catalogEntries(catalog interface{}) (entries []*generate.Entry, err error) {
+	add := func(name, comment string, source func() (*fmtstr.Message, error)) (err error) {
+		var message *fmtstr.Message
+		if message, err = source(); err == nil {
+			entries = append(entries, generate.NewEntry(name, comment, message))
+		}
+		return
+	}
+
+	switch file := catalog.(type) {
+	case *fmtstr.POFile:
+		for _, entry := range file.Entries {
+			if entry.IsHeader() || entry.Obsolete || entry.HasFlag(fmtstr.POFlagNoGoFormat) {
+				continue
+			}
+			if err = add(entry.Context, strings.Join(entry.ExtractedComments, "\n"), entry.Source); err != nil {
+				err = fmt.Errorf("%q: %w", entry.ID, err)
+				return
+			}
+		}
+	case *fmtstr.GotextFile:
+		for _, message := range file.Messages {
+			if err = add("", message.Comment, message.Source); err != nil {
+				err = fmt.Errorf("%q: %w", message.Message, err)
+				return
+			}
+		}
+	case *fmtstr.ARBFile:
+		for _, message := range file.Messages {
+			if err = add(message.Key, message.Description, message.Source); err != nil {
+				err = fmt.Errorf("%s: %w", message.Key, err)
+				return
+			}
+		}
+	case fmtstr.ChromeFile:
+		var keys []string
+		for key := range file {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err = add(key, file[key].Description, file[key].Source); err != nil {
+				err = fmt.Errorf("%s: %w", key, err)
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func init() {
+	register(&cCommand{
+		name:    "lint",
+		summary: "check the translations of message catalog files",
+		usage:   "[-template file] <file> [file...]",
+		setup: func(flags *flag.FlagSet) {
+			flags.String("template", "", "source ARB or messages.json file used to check the translations")
+		},
+		run: runLint,
+	})
+}
+
+// cLintIssue is a single problem found with a catalog message
+type cLintIssue struct {
+	key string
+	err error
+}
+
+func runLint(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) == 0 {
+		flags.Usage()
+		return gExitInvalid
+	}
+
+	var template interface{}
+	if path := flagString(flags, "template"); path != "" {
+		var err error
+		if template, err = readCatalog(path); err != nil {
+			return failf(stderr, gExitInvalid, "%s: %v", path, err)
+		}
+	}
+
+	var total int
+	for _, path := range args {
+		catalog, err := readCatalog(path)
+		if err != nil {
+			code = failf(stderr, gExitInvalid, "%s: %v", path, err)
+			continue
+		}
+
+		var issues []*cLintIssue
+		switch file := catalog.(type) {
+		case *fmtstr.POFile:
+			issues = lintPO(file)
+		case *fmtstr.GotextFile:
+			issues = lintGotext(file)
+		case *fmtstr.ARBFile:
+			source, _ := template.(*fmtstr.ARBFile)
+			issues = lintARB(file, source)
+		case fmtstr.ChromeFile:
+			source, _ := template.(fmtstr.ChromeFile)
+			issues = lintChrome(file, source)
+		}
+
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%s: %s: %v\n", path, issue.key, issue.err)
+		}
+		total += len(issues)
+	}
+
+	if total > 0 && code == gExitOK {
+		code = gExitFailed
+	}
+	return
+}
+
+// readCatalog reads the catalog file at the given path, the type of catalog
+// is derived from the file name: `.po` and `.pot` files are PO files, `.arb`
+// files are ARB files, `messages.json` files are Chrome i18n files and all
+// other `.json` files are gotext files
+func readCatalog(path string) (catalog interface{}, err error) {
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	defer file.Close()
+
+	switch name := strings.ToLower(filepath.Base(path)); {
+	case strings.HasSuffix(name, ".po"), strings.HasSuffix(name, ".pot"):
+		catalog, err = fmtstr.ReadPOFile(file)
+	case strings.HasSuffix(name, ".arb"):
+		catalog, err = fmtstr.ReadARBFile(file)
+	case name == "messages.json":
+		catalog, err = fmtstr.ReadChromeFile(file)
+	case strings.HasSuffix(name, ".json"):
+		catalog, err = fmtstr.ReadGotextFile(file)
+	default:
+		err = fmt.Errorf("unsupported catalog file type")
+	}
+	return
+}
+
+func lintPO(file *fmtstr.POFile) (issues []*cLintIssue) {
+	for _, entry := range file.Entries {
+		if entry.IsHeader() || entry.Obsolete {
+			continue
+		}
+		if err := entry.Validate(); err != nil {
+			key := entry.ID
+			if entry.Context != "" {
+				key = entry.Context + "|" + key
+			}
+			issues = append(issues, &cLintIssue{key: fmt.Sprintf("%q", key), err: err})
+		}
+	}
+	return
+}
+
+func lintGotext(file *fmtstr.GotextFile) (issues []*cLintIssue) {
+	for _, message := range file.Messages {
+		if message.Translation == "" {
+			continue
+		}
+		if _, err := message.Format(); err != nil {
+			var key string
+			if len(message.ID) > 0 {
+				key = message.ID[0]
+			}
+			issues = append(issues, &cLintIssue{key: fmt.Sprintf("%q", key), err: err})
+		}
+	}
+	return
+}
+
+func lintARB(file, template *fmtstr.ARBFile) (issues []*cLintIssue) {
+	for _, message := range file.Messages {
+		var err error
+		var source *fmtstr.Message
+		if template != nil {
+			if found := template.Lookup(message.Key); found == nil {
+				err = fmt.Errorf("message not found in template")
+			} else {
+				source, err = found.Source()
+			}
+		}
+		if err == nil {
+			_, err = message.Format(source)
+		}
+		if err != nil {
+			issues = append(issues, &cLintIssue{key: message.Key, err: err})
+		}
+	}
+	return
+}
+
+func lintChrome(file, template fmtstr.ChromeFile) (issues []*cLintIssue) {
+	var keys []string
+	for key := range file {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var err error
+		var source *fmtstr.Message
+		if template != nil {
+			if found, present := template[key]; !present {
+				err = fmt.Errorf("message not found in template")
+			} else {
+				source, err = found.Source()
+			}
+		}
+		if err == nil {
+			_, err = file[key].Format(source)
+		}
+		if err != nil {
+			issues = append(issues, &cLintIssue{key: key, err: err})
+		}
+	}
+	return
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func init() {
+	register(&cCommand{
+		name:    "normalize",
+		summary: "print the canonical form of format strings",
+		usage:   "[-collapse] <format> [format...]",
+		setup: func(flags *flag.FlagSet) {
+			flags.Bool("collapse", false, "also collapse synonymous verbs, see fmtstr.Collapse")
+		},
+		run: runNormalize,
+	})
+}
+
+func runNormalize(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) == 0 {
+		flags.Usage()
+		return gExitInvalid
+	}
+
+	normalize := fmtstr.Normalize
+	if flagBool(flags, "collapse") {
+		normalize = fmtstr.Collapse
+	}
+
+	for _, format := range args {
+		normalized, err := normalize(format)
+		if err != nil {
+			code = failf(stderr, gExitInvalid, "%q: %v", format, err)
+			continue
+		}
+		fmt.Fprintln(stdout, normalized)
+	}
+	return
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command fmtstr is a command-line interface to the fmtstr package.
+//
+// Usage:
+//
+//	fmtstr <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	decompose   print the replaced, labelled and variables of a format string
+//	compare     check that a translation is compatible with a source format
+//	normalize   print the canonical form of format strings
+//	convert     convert a format string between dialects
+//	lint        check the translations of message catalog files
+//	generate    generate typed message functions from catalog source messages
+//
+// The exit code is 0 on success, 1 when a check fails (ie: compare and lint
+// found problems) and 2 for usage errors or invalid input.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	gExitOK      = 0
+	gExitFailed  = 1
+	gExitInvalid = 2
+)
+
+// cCommand is a single fmtstr sub-command
+type cCommand struct {
+	name    string
+	summary string
+	usage   string
+	run     func(flags *flag.FlagSet, args []string, stdout, stderr io.Writer) (code int)
+	setup   func(flags *flag.FlagSet)
+}
+
+var gCommands = map[string]*cCommand{}
+
+func register(command *cCommand) {
+	gCommands[command.name] = command
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) (code int) {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return gExitInvalid
+		}
+		return gExitOK
+	}
+
+	command, present := gCommands[args[0]]
+	if !present {
+		fmt.Fprintf(stderr, "fmtstr: unknown command %q\n", args[0])
+		usage(stderr)
+		return gExitInvalid
+	}
+
+	flags := flag.NewFlagSet("fmtstr "+command.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: fmtstr %s %s\n\n%s\n", command.name, command.usage, command.summary)
+		flags.PrintDefaults()
+	}
+	if command.setup != nil {
+		command.setup(flags)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return gExitOK
+		}
+		return gExitInvalid
+	}
+	return command.run(flags, flags.Args(), stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	var names []string
+	for name := range gCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "usage: fmtstr <command> [flags] [arguments]\n\ncommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s  %s\n", name, gCommands[name].summary)
+	}
+}
+
+// failf prints the error message to stderr and returns the exit code given
+func failf(stderr io.Writer, code int, format string, argv ...interface{}) int {
+	fmt.Fprintf(stderr, "fmtstr: "+format+"\n", argv...)
+	return code
+}
+
+// splitArgv splits the comma-separated argv flag value
+func splitArgv(value string) (argv []string) {
+	if value != "" {
+		argv = strings.Split(value, ",")
+	}
+	return
+}
+
+// flagString returns the value of the named flag
+func flagString(flags *flag.FlagSet, name string) (value string) {
+	value = flags.Lookup(name).Value.String()
+	return
+}
+
+// flagBool returns true if the named boolean flag is set
+func flagBool(flags *flag.FlagSet, name string) (value bool) {
+	value = flagString(flags, name) == "true"
+	return
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testRun(args ...string) (code int, stdout, stderr string) {
+	var out, err bytes.Buffer
+	code = run(args, &out, &err)
+	stdout, stderr = out.String(), err.String()
+	return
+}
+
+func TestCommands(t *testing.T) {
+	Convey("usage", t, func() {
+		code, _, stderr := testRun()
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldContainSubstring, "decompose")
+		code, _, _ = testRun("help")
+		So(code, ShouldEqual, gExitOK)
+		code, _, stderr = testRun("nope")
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldStartWith, `fmtstr: unknown command "nope"`)
+		code, _, _ = testRun("decompose", "-h")
+		So(code, ShouldEqual, gExitOK)
+		code, _, _ = testRun("decompose", "-nope")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+
+	Convey("decompose", t, func() {
+		code, stdout, _ := testRun("decompose", "%s has %d items", ".Name", ".Count")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "replaced: %[1]s has %[2]d items\n"+
+			"labelled: {Name} has {Count} items\n"+
+			"variables:\n"+
+			"  1    %s       text   {Name}\n"+
+			"  2    %d       num    {Count}\n")
+
+		code, stdout, _ = testRun("decompose", "-json", "-dialect", "python", "%(name)s")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldContainSubstring, `"replaced": "%(name)s"`)
+		So(stdout, ShouldContainSubstring, `"labelled": "{Name}"`)
+		So(stdout, ShouldContainSubstring, `"name": "name"`)
+
+		code, _, _ = testRun("decompose")
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, stderr := testRun("decompose", "-dialect", "nope", "%s")
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldStartWith, `fmtstr: unknown dialect "nope"`)
+		code, _, _ = testRun("decompose", "%[1]d %[1]s")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+
+	Convey("compare", t, func() {
+		code, stdout, _ := testRun("compare", "-argv", ".Name,.Count", "%s has %d items", "%[2]d Artikel gehören %[1]s")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "ok\n")
+
+		code, _, stderr := testRun("compare", "%s has %d items", "%d Artikel")
+		So(code, ShouldEqual, gExitFailed)
+		So(stderr, ShouldContainSubstring, "invalid translation")
+
+		code, _, _ = testRun("compare", "%[1]d %[1]s", "%d")
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, _ = testRun("compare", "%s")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+
+	Convey("normalize", t, func() {
+		code, stdout, _ := testRun("normalize", "%[1]s %[2]d %0-+5d", "%[1]F")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "%s %d %+-5d\n%F\n")
+
+		code, stdout, _ = testRun("normalize", "-collapse", "%[1]F")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "%f\n")
+
+		code, stdout, _ = testRun("normalize", "%[1]d %[1]s", "%d")
+		So(code, ShouldEqual, gExitInvalid)
+		So(stdout, ShouldEqual, "%d\n")
+		code, _, _ = testRun("normalize")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+
+	Convey("convert", t, func() {
+		code, stdout, _ := testRun("convert", "-to", "c", "%[2]d of %[1]s")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "%2$d of %1$s\n")
+
+		code, _, stderr := testRun("convert", "-to", "icu", "%5d")
+		So(code, ShouldEqual, gExitFailed)
+		So(stderr, ShouldContainSubstring, "no equivalent")
+
+		code, _, _ = testRun("convert", "-from", "nope", "-to", "c", "%d")
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, _ = testRun("convert", "-to", "nope", "%d")
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, _ = testRun("convert", "-to", "c", "%[1]d %[1]s")
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, _ = testRun("convert", "%d")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+
+	Convey("lint", t, func() {
+		dir := t.TempDir()
+		write := func(name, content string) (path string) {
+			path = filepath.Join(dir, name)
+			So(os.WriteFile(path, []byte(content), 0644), ShouldEqual, nil)
+			return
+		}
+
+		po := write("de.po", "msgid \"\"\nmsgstr \"\"\n\"Language: de\\n\"\n\n#, go-format\nmsgid \"%s has %d items\"\nmsgstr \"%d Artikel\"\n\n#, go-format\nmsgid \"%d files\"\nmsgstr \"%d Dateien\"\n\n#, c-format\nmsgid \"%lu bytes\"\nmsgstr \"%lu Bytes\"\n\nmsgid \"50% off\"\nmsgstr \"50% Rabatt\"\n")
+		gotext := write("messages.gotext.json", `{"language": "de", "messages": [{"id": "{Count} items", "key": "%d items", "message": "{Count} items", "translation": "Artikel", "placeholders": [{"id": "Count", "string": "%[1]d", "type": "int", "underlyingType": "int", "argNum": 1, "expr": "count"}]}]}`)
+		arbTemplate := write("app_en.arb", `{"@@locale": "en", "items": "{Name} has {Count} items", "@items": {"placeholders": {"Name": {"type": "String"}, "Count": {"type": "int"}}}}`)
+		arb := write("app_de.arb", `{"@@locale": "de", "items": "{Count} Artikel gehören {Name}", "missing": "Hallo"}`)
+		chrome := write("messages.json", `{"items": {"message": "$count$ Artikel", "placeholders": {"count": {"content": "$1"}}}}`)
+
+		code, stdout, _ := testRun("lint", po, gotext)
+		So(code, ShouldEqual, gExitFailed)
+		So(stdout, ShouldContainSubstring, po+`: "%s has %d items": `)
+		So(stdout, ShouldNotContainSubstring, `"%d files"`)
+		So(stdout, ShouldNotContainSubstring, `"%lu bytes"`)
+		So(stdout, ShouldNotContainSubstring, `"50% off"`)
+		So(stdout, ShouldContainSubstring, gotext+`: "{Count} items": `)
+
+		code, stdout, _ = testRun("lint", "-template", arbTemplate, arb)
+		So(code, ShouldEqual, gExitFailed)
+		So(stdout, ShouldEqual, arb+": missing: message not found in template\n")
+
+		code, stdout, _ = testRun("lint", arbTemplate, chrome)
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "")
+
+		code, stdout, _ = testRun("lint", "-template", chrome, chrome)
+		So(code, ShouldEqual, gExitOK)
+
+		code, _, stderr := testRun("lint", filepath.Join(dir, "missing.po"))
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldContainSubstring, "missing.po")
+		code, _, stderr = testRun("lint", write("notes.txt", ""))
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldContainSubstring, "unsupported catalog file type")
+		code, _, _ = testRun("lint", "-template", filepath.Join(dir, "missing.arb"), arb)
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, _ = testRun("lint")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+
+	Convey("generate", t, func() {
+		dir := t.TempDir()
+		write := func(name, content string) (path string) {
+			path = filepath.Join(dir, name)
+			So(os.WriteFile(path, []byte(content), 0644), ShouldEqual, nil)
+			return
+		}
+
+		arb := write("app_en.arb", `{"@@locale": "en", "itemsInCart": "{count} items in {user}'s cart", "@itemsInCart": {"description": "cart summary", "placeholders": {"count": {"type": "int"}, "user": {"type": "String"}}}}`)
+		po := write("en.pot", "msgid \"\"\nmsgstr \"\"\n\n#. shown after login\n#, go-format\nmsgctxt \"welcome\"\nmsgid \"Welcome back, %s!\"\nmsgstr \"\"\n\n#, no-go-format\nmsgid \"100%\"\nmsgstr \"\"\n")
+
+		code, stdout, stderr := testRun("generate", "-package", "i18n", arb, po)
+		So(stderr, ShouldEqual, "")
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldStartWith, "// Code generated by fmtstr; DO NOT EDIT.\n\npackage i18n\n")
+		So(stdout, ShouldContainSubstring, "//\n// cart summary\nfunc ItemsInCart(p *message.Printer, count int, user string) string {\n\treturn p.Sprintf(\"%d items in %s's cart\", count, user)\n}\n")
+		So(stdout, ShouldContainSubstring, "//\n// shown after login\nfunc Welcome(p *message.Printer, text string) string {\n\treturn p.Sprintf(\"Welcome back, %s!\", text)\n}\n")
+		So(stdout, ShouldNotContainSubstring, "100%")
+
+		output := filepath.Join(dir, "messages.go")
+		code, stdout, _ = testRun("generate", "-o", output, arb)
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "")
+		data, err := os.ReadFile(output)
+		So(err, ShouldEqual, nil)
+		So(string(data), ShouldContainSubstring, "package messages\n")
+
+		test := filepath.Join(dir, "messages_test.go")
+		code, stdout, _ = testRun("generate", "-o", output, "-test", test, arb)
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldEqual, "")
+		data, err = os.ReadFile(test)
+		So(err, ShouldEqual, nil)
+		So(string(data), ShouldContainSubstring, "func TestItemsInCart(t *testing.T) {\n")
+		code, _, stderr = testRun("generate", "-lang", "ts", "-test", test, arb)
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldContainSubstring, "-test is only supported by -lang go")
+
+		code, stdout, _ = testRun("generate", "-lang", "ts", arb, po)
+		So(code, ShouldEqual, gExitOK)
+		So(stdout, ShouldContainSubstring, "export interface ItemsInCartArgs {\n  Count: number;\n  User: string;\n}\n")
+		So(stdout, ShouldContainSubstring, "export const WelcomeKey = \"Welcome back, %s!\";\n")
+		So(stdout, ShouldContainSubstring, "export function welcome(args: WelcomeArgs): MessageRequest<WelcomeArgs> {\n")
+
+		code, _, stderr = testRun("generate", arb, arb)
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldContainSubstring, `duplicate name "ItemsInCart"`)
+		code, _, stderr = testRun("generate", "-lang", "rust", arb)
+		So(code, ShouldEqual, gExitInvalid)
+		So(stderr, ShouldContainSubstring, `unknown language "rust"`)
+		code, _, _ = testRun("generate", filepath.Join(dir, "missing.arb"))
+		So(code, ShouldEqual, gExitInvalid)
+		code, _, _ = testRun("generate")
+		So(code, ShouldEqual, gExitInvalid)
+	})
+}
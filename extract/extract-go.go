@@ -0,0 +1,238 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+// DefaultGoFuncs are the printf-like functions of the standard library,
+// mapped to the index of their format argument
+var DefaultGoFuncs = map[string]int{
+	"fmt.Errorf":  0,
+	"fmt.Fprintf": 1,
+	"fmt.Printf":  0,
+	"fmt.Sprintf": 0,
+	"log.Fatalf":  0,
+	"log.Panicf":  0,
+	"log.Printf":  0,
+}
+
+// GoExtractor finds the calls to printf-like functions in Go sources
+type GoExtractor struct {
+	// Funcs are the printf-like function names mapped to the index of their
+	// format argument, where any arguments following the format are the
+	// format string arguments. The names are matched against the called
+	// expression, either exactly or as a dot-separated suffix, so that
+	// `printer.Sprintf` matches calls to `e.printer.Sprintf` and `Sprintf`
+	// matches all calls to any `Sprintf` method or package function
+	Funcs map[string]int
+}
+
+// NewGoExtractor constructs a new GoExtractor for the given printf-like
+// functions, using a copy of DefaultGoFuncs if funcs is nil
+func NewGoExtractor(funcs map[string]int) (e *GoExtractor) {
+	if funcs == nil {
+		funcs = make(map[string]int)
+		for name, idx := range DefaultGoFuncs {
+			funcs[name] = idx
+		}
+	}
+	e = &GoExtractor{Funcs: funcs}
+	return
+}
+
+// ExtractSource parses the given Go source file and returns a Message for
+// each of the printf-like function calls found, see ExtractFiles. The src
+// argument is the same as for go/parser.ParseFile and the file is read from
+// the filename if src is nil
+func (e *GoExtractor) ExtractSource(filename string, src interface{}) (messages []*Message, err error) {
+	fset := token.NewFileSet()
+	var file *ast.File
+	if file, err = parser.ParseFile(fset, filename, src, parser.SkipObjectResolution); err != nil {
+		return
+	}
+	messages, err = e.ExtractFiles(fset, file)
+	return
+}
+
+// ExtractDir parses all of the non-test Go source files in the given
+// directory, which are expected to be the same package, and returns the
+// ExtractFiles results
+func (e *GoExtractor) ExtractDir(path string) (messages []*Message, err error) {
+	var entries []os.DirEntry
+	if entries, err = os.ReadDir(path); err != nil {
+		return
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		var file *ast.File
+		if file, err = parser.ParseFile(fset, filepath.Join(path, name), nil, parser.SkipObjectResolution); err != nil {
+			return
+		}
+		files = append(files, file)
+	}
+
+	messages, err = e.ExtractFiles(fset, files...)
+	return
+}
+
+// ExtractFiles returns a Message for each of the printf-like function calls
+// found in the given files, in source order. The format argument is
+// resolved to a constant string, which may be a string literal, a
+// concatenation of constant strings or the name of a string constant
+// declared within any of the files given (constant scoping is not
+// considered). The argument expressions following the format are the argv
+// used to decompose the format string, ie: `fmt.Sprintf("%d items",
+// len(list))` has an argv of `len(list)`.
+//
+// When calls have a format which cannot be resolved (ErrNotLiteral) or the
+// format string cannot be decomposed, the errors are joined together,
+// prefixed with the call positions, and returned along with all of the valid
+// messages found
+func (e *GoExtractor) ExtractFiles(fset *token.FileSet, files ...*ast.File) (messages []*Message, err error) {
+	consts := make(map[string]ast.Expr)
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if decl, ok := node.(*ast.GenDecl); ok && decl.Tok == token.CONST {
+				for _, spec := range decl.Specs {
+					if value, ok := spec.(*ast.ValueSpec); ok && len(value.Names) == len(value.Values) {
+						for idx, name := range value.Names {
+							consts[name.Name] = value.Values[idx]
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	var errs []error
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			name := types.ExprString(call.Fun)
+			index, found := e.lookup(name)
+			if !found {
+				return true
+			}
+
+			position := fset.Position(call.Pos())
+			if index < 0 || index >= len(call.Args) {
+				errs = append(errs, fmt.Errorf("%v: %w: missing %s format", position, ErrNotLiteral, name))
+				return true
+			}
+			format, resolved := resolveConstant(call.Args[index], consts, nil)
+			if !resolved {
+				errs = append(errs, fmt.Errorf("%v: %w: %v", position, ErrNotLiteral, types.ExprString(call.Args[index])))
+				return true
+			}
+
+			var argv []string
+			for idx, arg := range call.Args[index+1:] {
+				expr := types.ExprString(arg)
+				if call.Ellipsis.IsValid() && index+1+idx == len(call.Args)-1 {
+					expr += "..."
+				}
+				argv = append(argv, expr)
+			}
+
+			message, problem := fmtstr.NewMessage(format, argv...)
+			if problem != nil {
+				errs = append(errs, fmt.Errorf("%v: %w", position, problem))
+				return true
+			}
+			messages = append(messages, &Message{
+				Message:  message,
+				Func:     name,
+				Position: position,
+			})
+			return true
+		})
+	}
+
+	sortMessages(messages)
+	err = errors.Join(errs...)
+	return
+}
+
+// lookup returns the format argument index of the function name given
+func (e *GoExtractor) lookup(name string) (index int, found bool) {
+	if index, found = e.Funcs[name]; found {
+		return
+	}
+	for idx := strings.IndexByte(name, '.'); idx > -1; idx = strings.IndexByte(name, '.') {
+		name = name[idx+1:]
+		if index, found = e.Funcs[name]; found {
+			return
+		}
+	}
+	return
+}
+
+// resolveConstant returns the constant string value of the given expression
+func resolveConstant(expr ast.Expr, consts map[string]ast.Expr, seen map[string]struct{}) (value string, ok bool) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if x.Kind == token.STRING {
+			var err error
+			value, err = strconv.Unquote(x.Value)
+			ok = err == nil
+		}
+	case *ast.ParenExpr:
+		value, ok = resolveConstant(x.X, consts, seen)
+	case *ast.BinaryExpr:
+		if x.Op == token.ADD {
+			var left, right string
+			if left, ok = resolveConstant(x.X, consts, seen); ok {
+				if right, ok = resolveConstant(x.Y, consts, seen); ok {
+					value = left + right
+				}
+			}
+		}
+	case *ast.Ident:
+		if found, present := consts[x.Name]; present {
+			if _, cyclic := seen[x.Name]; !cyclic {
+				if seen == nil {
+					seen = make(map[string]struct{})
+				}
+				seen[x.Name] = struct{}{}
+				value, ok = resolveConstant(found, consts, seen)
+				delete(seen, x.Name)
+			}
+		}
+	}
+	return
+}
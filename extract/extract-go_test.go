@@ -0,0 +1,112 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testGoSource = `package example
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	greeting = "Hello " + subject
+	subject  = "%s!"
+	cyclic   = cyclic + "x"
+)
+
+type thing struct {
+	printer interface{ Sprintf(string, ...interface{}) string }
+}
+
+func (t *thing) run(name string, list []string, args ...interface{}) error {
+	fmt.Println(fmt.Sprintf(greeting, name))
+	fmt.Fprintf(os.Stderr, "%d items"+" (%s)", len(list), t.printer.Sprintf("%[2]s of %[1]d", 1, "x"))
+	fmt.Printf(name)
+	fmt.Printf(cyclic)
+	fmt.Printf("%[1]d %[1]s", 1)
+	return fmt.Errorf("%v: %w", args...)
+}
+`
+
+func TestGoExtractor(t *testing.T) {
+	Convey("ExtractSource", t, func() {
+		e := NewGoExtractor(nil)
+		e.Funcs["printer.Sprintf"] = 0
+		messages, err := e.ExtractSource("example.go", testGoSource)
+		So(len(messages), ShouldEqual, 4)
+
+		So(messages[0].Func, ShouldEqual, "fmt.Sprintf")
+		So(messages[0].Format, ShouldEqual, "Hello %s!")
+		So(messages[0].Argv, ShouldResemble, []string{"name"})
+		So(messages[0].Position.String(), ShouldEqual, "example.go:19:14")
+
+		So(messages[1].Func, ShouldEqual, "fmt.Fprintf")
+		So(messages[1].Format, ShouldEqual, "%d items (%s)")
+		So(messages[1].Argv, ShouldResemble, []string{"len(list)", `t.printer.Sprintf("%[2]s of %[1]d", 1, "x")`})
+
+		So(messages[2].Func, ShouldEqual, "t.printer.Sprintf")
+		So(messages[2].Replaced, ShouldEqual, "%[2]s of %[1]d")
+
+		So(messages[3].Func, ShouldEqual, "fmt.Errorf")
+		So(messages[3].Format, ShouldEqual, "%v: %w")
+		So(messages[3].Labelled, ShouldEqual, "{Args}: {Error}")
+		So(messages[3].Argv, ShouldResemble, []string{"args..."})
+
+		So(errors.Is(err, ErrNotLiteral), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "example.go:21:2: format is not a literal string: name\n"+
+			"example.go:22:2: format is not a literal string: cyclic\n"+
+			"example.go:23:2: conflicting substitution types: %[1]d != %[1]s")
+
+		messages, err = NewGoExtractor(map[string]int{"Sprintf": 0}).ExtractSource("example.go", testGoSource)
+		So(err, ShouldEqual, nil)
+		So(len(messages), ShouldEqual, 2)
+
+		messages, err = NewGoExtractor(map[string]int{"fmt.Fprintf": 4}).ExtractSource("example.go", testGoSource)
+		So(len(messages), ShouldEqual, 0)
+		So(err.Error(), ShouldEqual, "example.go:20:2: format is not a literal string: missing fmt.Fprintf format")
+
+		_, err = NewGoExtractor(nil).ExtractSource("bad.go", "package")
+		So(err, ShouldNotEqual, nil)
+	})
+
+	Convey("ExtractDir", t, func() {
+		dir := t.TempDir()
+		So(os.WriteFile(filepath.Join(dir, "a.go"), []byte("package p\n\nconst format = \"%d items\"\n"), 0644), ShouldEqual, nil)
+		So(os.WriteFile(filepath.Join(dir, "b.go"), []byte("package p\n\nimport \"fmt\"\n\nvar _ = fmt.Sprintf(format, 3)\n"), 0644), ShouldEqual, nil)
+		So(os.WriteFile(filepath.Join(dir, "b_test.go"), []byte("package p\n\nimport \"fmt\"\n\nvar _ = fmt.Sprintf(\"%s\", 3)\n"), 0644), ShouldEqual, nil)
+
+		messages, err := NewGoExtractor(nil).ExtractDir(dir)
+		So(err, ShouldEqual, nil)
+		So(len(messages), ShouldEqual, 1)
+		So(messages[0].Format, ShouldEqual, "%d items")
+		So(messages[0].Position.Filename, ShouldEqual, filepath.Join(dir, "b.go"))
+
+		_, err = NewGoExtractor(nil).ExtractDir(filepath.Join(dir, "missing"))
+		So(err, ShouldNotEqual, nil)
+		So(os.WriteFile(filepath.Join(dir, "c.go"), []byte("package"), 0644), ShouldEqual, nil)
+		_, err = NewGoExtractor(nil).ExtractDir(dir)
+		So(err, ShouldNotEqual, nil)
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"strings"
+	"text/template/parse"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+// DefaultTemplateFunc is the name of the translation function used when a
+// TemplateExtractor has no Funcs configured
+const DefaultTemplateFunc = "_"
+
+// TemplateExtractor finds the translation function calls in text/template
+// and html/template sources, which share the same template syntax
+type TemplateExtractor struct {
+	// Funcs are the names of the translation functions, where the first
+	// argument is the format string and any remaining arguments are the
+	// format string arguments
+	Funcs []string
+	// LeftDelim and RightDelim are the template action delimiters, the
+	// defaults are `{{` and `}}`
+	LeftDelim  string
+	RightDelim string
+}
+
+// NewTemplateExtractor constructs a new TemplateExtractor for the given
+// translation function names, using DefaultTemplateFunc if none are given
+func NewTemplateExtractor(funcs ...string) (e *TemplateExtractor) {
+	if len(funcs) == 0 {
+		funcs = []string{DefaultTemplateFunc}
+	}
+	e = &TemplateExtractor{Funcs: funcs}
+	return
+}
+
+// ExtractFile is a convenience wrapper around Extract which reads the source
+// from the file at the given path
+func (e *TemplateExtractor) ExtractFile(path string) (messages []*Message, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return
+	}
+	messages, err = e.Extract(path, string(data))
+	return
+}
+
+// Extract parses the given template source and returns a Message for each of
+// the translation function calls found, in source order. Calls look like
+// `{{ _ "%s has %d items" .Name .Count }}`, where the argument expressions
+// (ie: `.Name` and `.Count`) are the argv used to decompose the format
+// string. Piped values are the last argument, so
+// `{{ .Count | _ "%d items" }}` has an argv of `.Count`.
+//
+// The template functions do not need to be defined and all of the nested
+// templates (ie: `{{ define }}` blocks) are examined. When calls have a
+// format which is not a string literal (ErrNotLiteral) or the format string
+// cannot be decomposed, the errors are joined together, prefixed with the
+// call positions, and returned along with all of the valid messages found
+func (e *TemplateExtractor) Extract(filename, source string) (messages []*Message, err error) {
+	funcs := make(map[string]struct{})
+	for _, name := range e.Funcs {
+		funcs[name] = struct{}{}
+	}
+	if len(funcs) == 0 {
+		funcs[DefaultTemplateFunc] = struct{}{}
+	}
+
+	tree := parse.New(filename)
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err = tree.Parse(source, e.LeftDelim, e.RightDelim, trees); err != nil {
+		return
+	}
+
+	w := &cTemplateWalker{
+		filename: filename,
+		source:   source,
+		funcs:    funcs,
+	}
+	for _, t := range trees {
+		if t.Root != nil {
+			w.walk(t.Root)
+		}
+	}
+
+	sortMessages(w.messages)
+	messages = w.messages
+	err = errors.Join(w.errs...)
+	return
+}
+
+type cTemplateWalker struct {
+	filename string
+	source   string
+	funcs    map[string]struct{}
+	messages []*Message
+	errs     []error
+}
+
+func (w *cTemplateWalker) walk(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, child := range n.Nodes {
+				w.walk(child)
+			}
+		}
+	case *parse.ActionNode:
+		w.walk(n.Pipe)
+	case *parse.IfNode:
+		w.walkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		w.walkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		w.walkBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		w.walk(n.Pipe)
+	case *parse.ChainNode:
+		w.walk(n.Node)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for idx, cmd := range n.Cmds {
+			var piped parse.Node
+			if idx > 0 {
+				piped = n.Cmds[idx-1]
+			}
+			w.command(cmd, piped)
+			for _, arg := range cmd.Args {
+				w.walk(arg)
+			}
+		}
+	}
+}
+
+func (w *cTemplateWalker) walkBranch(n *parse.BranchNode) {
+	w.walk(n.Pipe)
+	w.walk(n.List)
+	w.walk(n.ElseList)
+}
+
+// command records the translation function call of the given command, if
+// it is one, where piped is the preceding command of the pipeline
+func (w *cTemplateWalker) command(cmd *parse.CommandNode, piped parse.Node) {
+	if len(cmd.Args) == 0 {
+		return
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return
+	} else if _, present := w.funcs[ident.Ident]; !present {
+		return
+	}
+
+	position := w.position(cmd.Position())
+	if len(cmd.Args) < 2 {
+		w.errs = append(w.errs, fmt.Errorf("%v: %w: missing %s format", position, ErrNotLiteral, ident.Ident))
+		return
+	}
+	literal, ok := cmd.Args[1].(*parse.StringNode)
+	if !ok {
+		w.errs = append(w.errs, fmt.Errorf("%v: %w: %v", position, ErrNotLiteral, cmd.Args[1]))
+		return
+	}
+
+	var argv []string
+	for _, arg := range cmd.Args[2:] {
+		if _, nested := arg.(*parse.PipeNode); nested {
+			argv = append(argv, "("+arg.String()+")")
+			continue
+		}
+		argv = append(argv, arg.String())
+	}
+	if piped != nil {
+		argv = append(argv, piped.String())
+	}
+
+	message, err := fmtstr.NewMessage(literal.Text, argv...)
+	if err != nil {
+		w.errs = append(w.errs, fmt.Errorf("%v: %w", position, err))
+		return
+	}
+	w.messages = append(w.messages, &Message{
+		Message:  message,
+		Func:     ident.Ident,
+		Position: position,
+	})
+}
+
+// position returns the line and column of the given byte offset
+func (w *cTemplateWalker) position(pos parse.Pos) (position token.Position) {
+	offset := int(pos)
+	if offset > len(w.source) {
+		offset = len(w.source)
+	}
+	before := w.source[:offset]
+	position = token.Position{
+		Filename: w.filename,
+		Offset:   offset,
+		Line:     1 + strings.Count(before, "\n"),
+		Column:   1 + offset - (strings.LastIndexByte(before, '\n') + 1),
+	}
+	return
+}
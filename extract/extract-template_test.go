@@ -0,0 +1,98 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+const testTemplate = `<h1>{{ _ "Hello %s!" .User.Name }}</h1>
+{{ if .Items }}
+  <p>{{ _ "%s has %d items" $.User.Name (len .Items) }}</p>
+{{ else }}
+  <p>{{ .Count | _ "%d items" }}</p>
+{{ end }}
+{{ define "footer" }}{{ range .Links }}<a>{{ tr "Visit %[1]s" .Title }}</a>{{ end }}{{ end }}
+{{ printf "%d" (_ "nested %d" .Count) }}
+`
+
+func TestTemplateExtractor(t *testing.T) {
+	Convey("Extract", t, func() {
+		messages, err := NewTemplateExtractor().Extract("page.tmpl", testTemplate)
+		So(err, ShouldEqual, nil)
+		So(len(messages), ShouldEqual, 4)
+
+		So(messages[0].Func, ShouldEqual, "_")
+		So(messages[0].Format, ShouldEqual, "Hello %s!")
+		So(messages[0].Argv, ShouldResemble, []string{".User.Name"})
+		So(messages[0].Labelled, ShouldEqual, "Hello {UserName}!")
+		So(messages[0].Position.String(), ShouldEqual, "page.tmpl:1:8")
+
+		So(messages[1].Argv, ShouldResemble, []string{"$.User.Name", "(len .Items)"})
+		So(messages[1].Position.Line, ShouldEqual, 3)
+
+		So(messages[2].Format, ShouldEqual, "%d items")
+		So(messages[2].Argv, ShouldResemble, []string{".Count"})
+		So(messages[2].Labelled, ShouldEqual, "{Count} items")
+
+		So(messages[3].Format, ShouldEqual, "nested %d")
+		So(messages[3].Position.Line, ShouldEqual, 8)
+
+		messages, err = NewTemplateExtractor("_", "tr").Extract("page.tmpl", testTemplate)
+		So(err, ShouldEqual, nil)
+		So(len(messages), ShouldEqual, 5)
+		So(messages[3].Func, ShouldEqual, "tr")
+		So(messages[3].Replaced, ShouldEqual, "Visit %[1]s")
+		So(messages[3].Position.Line, ShouldEqual, 7)
+	})
+
+	Convey("Delimiters", t, func() {
+		e := &TemplateExtractor{Funcs: []string{"t"}, LeftDelim: "[[", RightDelim: "]]"}
+		messages, err := e.Extract("page.tmpl", `{{ ignored }} [[ t "%d" .N ]]`)
+		So(err, ShouldEqual, nil)
+		So(len(messages), ShouldEqual, 1)
+		So(messages[0].Position.Column, ShouldEqual, 18)
+	})
+
+	Convey("ExtractFile", t, func() {
+		path := filepath.Join(t.TempDir(), "page.tmpl")
+		So(os.WriteFile(path, []byte(testTemplate), 0644), ShouldEqual, nil)
+		messages, err := NewTemplateExtractor().ExtractFile(path)
+		So(err, ShouldEqual, nil)
+		So(len(messages), ShouldEqual, 4)
+		So(messages[0].Position.Filename, ShouldEqual, path)
+
+		_, err = NewTemplateExtractor().ExtractFile(path + ".missing")
+		So(err, ShouldNotEqual, nil)
+	})
+
+	Convey("Errors", t, func() {
+		_, err := NewTemplateExtractor().Extract("bad.tmpl", `{{ if }}`)
+		So(err, ShouldNotEqual, nil)
+
+		messages, err := NewTemplateExtractor().Extract("page.tmpl", "{{ _ .Format }}\n{{ _ }}\n{{ _ \"%[1]d %[1]s\" .X }}\n{{ _ \"ok\" }}")
+		So(len(messages), ShouldEqual, 1)
+		So(errors.Is(err, ErrNotLiteral), ShouldBeTrue)
+		So(errors.Is(err, fmtstr.ErrInvalidTranslation), ShouldBeFalse)
+		So(err.Error(), ShouldStartWith, "page.tmpl:1:4: format is not a literal string: .Format\npage.tmpl:2:4: format is not a literal string: missing _ format\npage.tmpl:3:4: ")
+	})
+}
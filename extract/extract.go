@@ -0,0 +1,52 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extract provides source code extractors for finding the fmt format
+// strings passed to translation functions, along with the argument
+// expressions used to label the format string Variables, see
+// fmtstr.Decompose
+package extract
+
+import (
+	"errors"
+	"go/token"
+	"sort"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+var (
+	ErrNotLiteral = errors.New("format is not a literal string")
+)
+
+// Message is a decomposed format string found by an extractor
+type Message struct {
+	*fmtstr.Message
+
+	// Func is the name of the function called
+	Func string
+	// Position is the source location of the function call
+	Position token.Position
+}
+
+// sortMessages sorts the given messages by their source positions
+func sortMessages(messages []*Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		a, b := messages[i].Position, messages[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+}
@@ -0,0 +1,471 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+var (
+	ErrInvalidARB = errors.New("invalid ARB data")
+)
+
+const (
+	ARBTypeString = "String"
+	ARBTypeInt    = "int"
+	ARBTypeDouble = "double"
+	ARBTypeObject = "Object"
+)
+
+// ARBFile is a Flutter Application Resource Bundle, the ARB messages are in
+// the order of the JSON data and the Attributes are any of the global `@@`
+// attributes other than `@@locale`, keyed without the `@@` prefix
+type ARBFile struct {
+	Locale     string
+	Attributes map[string]json.RawMessage
+	Messages   []*ARBMessage
+}
+
+// ARBMessage is a single ARB message, where the Message is the ICU form of
+// the format string with each placeholder replaced by its curly-braced Name
+type ARBMessage struct {
+	Key          string
+	Message      string
+	Description  string
+	Placeholders ARBPlaceholders
+}
+
+// ARBPlaceholder is the metadata of a single ARB message placeholder
+type ARBPlaceholder struct {
+	Name               string                 `json:"-"`
+	Type               string                 `json:"type,omitempty"`
+	Example            string                 `json:"example,omitempty"`
+	Format             string                 `json:"format,omitempty"`
+	OptionalParameters map[string]interface{} `json:"optionalParameters,omitempty"`
+}
+
+// ARBPlaceholders is the list of ARB message placeholders, in the order of
+// the JSON data
+type ARBPlaceholders []*ARBPlaceholder
+
+// NewARBFile constructs a new ARBFile for the given locale
+func NewARBFile(locale string, messages ...*ARBMessage) (file *ARBFile) {
+	file = &ARBFile{
+		Locale:   locale,
+		Messages: messages,
+	}
+	return
+}
+
+// ReadARBFile parses the ARB data from the given reader
+func ReadARBFile(r io.Reader) (file *ARBFile, err error) {
+	file = &ARBFile{}
+	if err = json.NewDecoder(r).Decode(file); err != nil {
+		file = nil
+	}
+	return
+}
+
+// Write writes the ARB data to the given writer, using two-space indentation
+func (f *ARBFile) Write(w io.Writer) (err error) {
+	var data []byte
+	if data, err = json.Marshal(f); err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, data, "", "  "); err != nil {
+		return
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(w)
+	return
+}
+
+// Lookup returns the ARBMessage with the given Key, or nil if not found
+func (f *ARBFile) Lookup(key string) (message *ARBMessage) {
+	for _, message = range f.Messages {
+		if message.Key == key {
+			return
+		}
+	}
+	message = nil
+	return
+}
+
+func (f *ARBFile) MarshalJSON() (data []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	write := func(key string, value interface{}) {
+		if err != nil {
+			return
+		}
+		var encoded []byte
+		if encoded, err = jsonMarshal(value); err == nil {
+			if buf.Len() > 1 {
+				buf.WriteByte(',')
+			}
+			key, _ := jsonMarshal(key)
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(encoded)
+		}
+	}
+
+	if f.Locale != "" {
+		write("@@locale", f.Locale)
+	}
+	var names []string
+	for name := range f.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		write("@@"+name, f.Attributes[name])
+	}
+
+	for _, message := range f.Messages {
+		write(message.Key, message.Message)
+		if message.Description != "" || len(message.Placeholders) > 0 {
+			write("@"+message.Key, &cARBMetadata{
+				Description:  message.Description,
+				Placeholders: message.Placeholders,
+			})
+		}
+	}
+
+	if err == nil {
+		buf.WriteByte('}')
+		data = buf.Bytes()
+	}
+	return
+}
+
+func (f *ARBFile) UnmarshalJSON(data []byte) (err error) {
+	metadata := make(map[string]*cARBMetadata)
+	*f = ARBFile{}
+
+	err = jsonObject(data, func(key string, value json.RawMessage) (err error) {
+		switch {
+		case key == "@@locale":
+			err = json.Unmarshal(value, &f.Locale)
+		case strings.HasPrefix(key, "@@"):
+			if f.Attributes == nil {
+				f.Attributes = make(map[string]json.RawMessage)
+			}
+			f.Attributes[key[2:]] = value
+		case strings.HasPrefix(key, "@"):
+			meta := &cARBMetadata{}
+			if err = json.Unmarshal(value, meta); err == nil {
+				metadata[key[1:]] = meta
+			}
+		default:
+			message := &ARBMessage{Key: key}
+			if err = json.Unmarshal(value, &message.Message); err == nil {
+				f.Messages = append(f.Messages, message)
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("%w: %q: %w", ErrInvalidARB, key, err)
+		}
+		return
+	})
+
+	for _, message := range f.Messages {
+		if meta, present := metadata[message.Key]; present {
+			message.Description = meta.Description
+			message.Placeholders = meta.Placeholders
+		}
+	}
+	return
+}
+
+// cARBMetadata is the `@key` metadata of an ARB message
+type cARBMetadata struct {
+	Description  string          `json:"description,omitempty"`
+	Placeholders ARBPlaceholders `json:"placeholders,omitempty"`
+}
+
+func (p ARBPlaceholders) MarshalJSON() (data []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for idx, placeholder := range p {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		var encoded []byte
+		if encoded, err = jsonMarshal(placeholder.Name); err != nil {
+			return
+		}
+		buf.Write(encoded)
+		buf.WriteByte(':')
+		if encoded, err = jsonMarshal(placeholder); err != nil {
+			return
+		}
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	data = buf.Bytes()
+	return
+}
+
+func (p *ARBPlaceholders) UnmarshalJSON(data []byte) (err error) {
+	*p = nil
+	err = jsonObject(data, func(key string, value json.RawMessage) (err error) {
+		placeholder := &ARBPlaceholder{}
+		if err = json.Unmarshal(value, placeholder); err == nil {
+			placeholder.Name = key
+			*p = append(*p, placeholder)
+		}
+		return
+	})
+	return
+}
+
+// Lookup returns the ARBPlaceholder with the given Name, or nil if not found
+func (p ARBPlaceholders) Lookup(name string) (placeholder *ARBPlaceholder) {
+	for _, placeholder = range p {
+		if placeholder.Name == name {
+			return
+		}
+	}
+	placeholder = nil
+	return
+}
+
+// NewARBMessage returns the ARB message for the given source Message. Each
+// of the Variables are mapped to a placeholder: the Label is the Name, the
+// Variable.Example is the Example and the Type is derived from the Variable
+// Type ("int" for num, "double" for float, "String" for text and "Object"
+// for everything else). Float Variables with a precision are given the
+// "decimalPatternDigits" Format with the precision as the "decimalDigits"
+// optional parameter. Literal text is quoted as ICU MessageFormat text, where
+// apostrophes are doubled and braces are wrapped in apostrophes
+func NewARBMessage(key, description string, source *Message) (message *ARBMessage, err error) {
+	var segments Segments
+	if segments, _, err = Parse(source.Format, source.Argv...); err != nil {
+		return
+	}
+
+	message = &ARBMessage{
+		Key:         key,
+		Description: description,
+	}
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			message.Message += cICUDialect{}.escape(segment.Text)
+			continue
+		}
+		message.Message += "{" + segment.Variable.Label + "}"
+	}
+
+	for _, variable := range source.Variables {
+		placeholder := &ARBPlaceholder{
+			Name:    variable.Label,
+			Example: variable.Example(),
+		}
+		switch variable.Type {
+		case "num":
+			placeholder.Type = ARBTypeInt
+		case "float":
+			placeholder.Type = ARBTypeDouble
+			if variable.Has(ModDecimal) {
+				placeholder.Format = "decimalPatternDigits"
+				placeholder.OptionalParameters = map[string]interface{}{
+					"decimalDigits": variable.Precision,
+				}
+			}
+		case "text":
+			placeholder.Type = ARBTypeString
+		default:
+			placeholder.Type = ARBTypeObject
+		}
+		message.Placeholders = append(message.Placeholders, placeholder)
+	}
+	return
+}
+
+// Source returns the source Message for this ARB message, made from the
+// placeholder metadata. Each placeholder is an argument, in the order of the
+// Placeholders, with the Name as the argv entry and the verb derived from
+// the Type: %d for "int", %f for "double" (or %.Nf when the "decimalDigits"
+// optional parameter is present), %s for "String" and %v for everything else
+func (m *ARBMessage) Source() (source *Message, err error) {
+	var argv []string
+	lookup := make(map[string]*Variable)
+	for idx, placeholder := range m.Placeholders {
+		variable := &Variable{Pos: idx + 1, Verb: "v"}
+		switch placeholder.Type {
+		case ARBTypeInt:
+			variable.Verb = "d"
+		case ARBTypeDouble:
+			variable.Verb = "f"
+			switch digits := placeholder.OptionalParameters["decimalDigits"].(type) {
+			case float64:
+				variable.Precision = int(digits)
+				variable.Modifiers |= ModDecimal
+			case int:
+				variable.Precision = digits
+				variable.Modifiers |= ModDecimal
+			}
+		case ARBTypeString:
+			variable.Verb = "s"
+		}
+		lookup[placeholder.Name] = variable
+		argv = append(argv, placeholder.Name)
+	}
+
+	var segments Segments
+	if segments, err = m.segments(func(name string) *Variable {
+		return lookup[name]
+	}); err == nil {
+		source, err = NewMessage(segments.String(), argv...)
+	}
+	return
+}
+
+// Format returns the fmt format string of this ARB message, made by
+// replacing the curly-braced placeholder names with the source Variables of
+// the same Label, and checks that it is a valid translation of the source
+// Message (see Message.Check). Placeholder names are also matched to the
+// CamelCase Labels of the source Variables. When the source Message is nil,
+// the ARB message Source is used
+func (m *ARBMessage) Format(source *Message) (format string, err error) {
+	if source == nil {
+		if source, err = m.Source(); err != nil {
+			return
+		}
+	}
+
+	lookup := make(map[string]*Variable)
+	for _, variable := range source.Variables {
+		lookup[variable.Label] = variable
+	}
+
+	var segments Segments
+	if segments, err = m.segments(func(name string) (variable *Variable) {
+		if variable = lookup[name]; variable == nil {
+			variable = lookup[strcase.ToCamel(name)]
+		}
+		return
+	}); err != nil {
+		return
+	}
+
+	format = segments.String()
+	if _, err = source.Check(format); err != nil {
+		format = ""
+	}
+	return
+}
+
+// segments splits the Message into Segments, where each of the
+// curly-braced names found by the given lookup function are replaced with
+// their Variable, ICU apostrophe quoting is unescaped and ICU arguments with
+// a comma (ie: plural and select) are not supported
+func (m *ARBMessage) segments(lookup func(name string) *Variable) (segments Segments, err error) {
+	var text strings.Builder
+	last := len(m.Message) - 1
+	for i := 0; i <= last; i++ {
+		if m.Message[i] == '\'' && i < last {
+			// ICU apostrophe quoting, see ICUDialect
+			if m.Message[i+1] == '\'' {
+				text.WriteByte('\'')
+				i += 1
+				continue
+			} else if strings.IndexByte("{}#|", m.Message[i+1]) > -1 {
+				for i += 1; i <= last; i++ {
+					if m.Message[i] == '\'' {
+						if i < last && m.Message[i+1] == '\'' {
+							text.WriteByte('\'')
+							i += 1
+							continue
+						}
+						break
+					}
+					text.WriteByte(m.Message[i])
+				}
+				continue
+			}
+		}
+		if m.Message[i] == '{' {
+			if end := strings.IndexByte(m.Message[i+1:], '}'); end > -1 {
+				name := strings.TrimSpace(m.Message[i+1 : i+1+end])
+				if strings.Contains(name, ",") {
+					err = fmt.Errorf("%w: %q argument in message %q", ErrNoEquivalent, name, m.Key)
+					return
+				} else if variable := lookup(name); variable != nil {
+					if text.Len() > 0 {
+						segments = append(segments, &Segment{Text: text.String()})
+						text.Reset()
+					}
+					segments = append(segments, &Segment{Variable: variable})
+					i += end + 1
+					continue
+				}
+			}
+		}
+		text.WriteByte(m.Message[i])
+	}
+	if text.Len() > 0 {
+		segments = append(segments, &Segment{Text: text.String()})
+	}
+	return
+}
+
+// jsonMarshal is json.Marshal without escaping HTML characters
+func jsonMarshal(value interface{}) (data []byte, err error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err = encoder.Encode(value); err == nil {
+		data = bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	}
+	return
+}
+
+// jsonObject calls fn for each of the key and value pairs of the JSON object
+// given, in the order of the JSON data
+func jsonObject(data []byte, fn func(key string, value json.RawMessage) error) (err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var token json.Token
+	if token, err = decoder.Token(); err != nil {
+		return
+	} else if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		err = fmt.Errorf("expected a JSON object, found: %v", token)
+		return
+	}
+	for decoder.More() {
+		if token, err = decoder.Token(); err != nil {
+			return
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return
+		} else if err = fn(key, value); err != nil {
+			return
+		}
+	}
+	_, err = decoder.Token()
+	return
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testARBJSON = `{
+  "@@locale": "en",
+  "@@last_modified": "2024-01-01",
+  "itemCount": "{Name} has {Count} items",
+  "@itemCount": {
+    "description": "inventory summary",
+    "placeholders": {
+      "Name": {
+        "type": "String",
+        "example": "Name"
+      },
+      "Count": {
+        "type": "int",
+        "example": "42"
+      }
+    }
+  },
+  "price": "Total: {amount}",
+  "@price": {
+    "placeholders": {
+      "amount": {
+        "type": "double",
+        "format": "decimalPatternDigits",
+        "optionalParameters": {
+          "decimalDigits": 2
+        }
+      }
+    }
+  },
+  "hello": "Hello!"
+}
+`
+
+func TestARB(t *testing.T) {
+	source, _ := NewMessage("%s has %d items", ".Name", ".Count")
+
+	Convey("NewARBMessage", t, func() {
+		message, err := NewARBMessage("itemCount", "inventory summary", source)
+		So(err, ShouldEqual, nil)
+		So(message.Message, ShouldEqual, "{Name} has {Count} items")
+		So(len(message.Placeholders), ShouldEqual, 2)
+		So(message.Placeholders[1], ShouldResemble, &ARBPlaceholder{Name: "Count", Type: ARBTypeInt, Example: "42"})
+
+		price, _ := NewMessage("Total: %.2f (100%%)", ".Amount")
+		message, err = NewARBMessage("price", "", price)
+		So(err, ShouldEqual, nil)
+		So(message.Message, ShouldEqual, "Total: {Amount} (100%)")
+		So(message.Placeholders[0], ShouldResemble, &ARBPlaceholder{
+			Name:               "Amount",
+			Type:               ARBTypeDouble,
+			Example:            "3.14",
+			Format:             "decimalPatternDigits",
+			OptionalParameters: map[string]interface{}{"decimalDigits": 2},
+		})
+
+		quoted, _ := NewMessage("it's {%s} of %d", ".Name", ".Count")
+		message, err = NewARBMessage("quoted", "", quoted)
+		So(err, ShouldEqual, nil)
+		So(message.Message, ShouldEqual, "it''s '{'{Name}'}' of {Count}")
+		format, err := message.Format(quoted)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "it's {%s} of %d")
+		format, err = message.Format(nil)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "it's {%s} of %d")
+
+		// lone apostrophes are literal text
+		message.Message = "it's {Name}: {Count}"
+		format, err = message.Format(quoted)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "it's %s: %d")
+	})
+
+	Convey("ReadARBFile and Write", t, func() {
+		file, err := ReadARBFile(strings.NewReader(testARBJSON))
+		So(err, ShouldEqual, nil)
+		So(file.Locale, ShouldEqual, "en")
+		So(string(file.Attributes["last_modified"]), ShouldEqual, `"2024-01-01"`)
+		So(len(file.Messages), ShouldEqual, 3)
+		So(file.Lookup("itemCount").Description, ShouldEqual, "inventory summary")
+		So(file.Lookup("itemCount").Placeholders.Lookup("Count").Type, ShouldEqual, ARBTypeInt)
+		So(file.Lookup("nope"), ShouldBeNil)
+
+		var buf bytes.Buffer
+		So(file.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, testARBJSON)
+
+		_, err = ReadARBFile(strings.NewReader(`{"@key": "nope"}`))
+		So(errors.Is(err, ErrInvalidARB), ShouldBeTrue)
+		_, err = ReadARBFile(strings.NewReader(`[]`))
+		So(err, ShouldNotEqual, nil)
+	})
+
+	Convey("Source and Format", t, func() {
+		file, _ := ReadARBFile(strings.NewReader(testARBJSON))
+
+		found, err := file.Lookup("itemCount").Source()
+		So(err, ShouldEqual, nil)
+		So(found.Format, ShouldEqual, "%s has %d items")
+		So(found.Argv, ShouldResemble, []string{"Name", "Count"})
+
+		found, err = file.Lookup("price").Source()
+		So(err, ShouldEqual, nil)
+		So(found.Format, ShouldEqual, "Total: %.2f")
+		So(found.Variables[0].Label, ShouldEqual, "Amount")
+
+		translated := &ARBMessage{Key: "itemCount", Message: "{Count} Artikel gehören {Name}"}
+		format, err := translated.Format(source)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[2]d Artikel gehören %[1]s")
+
+		translated = &ARBMessage{Key: "price", Message: "Gesamt: {amount} (100%)"}
+		translated.Placeholders = file.Lookup("price").Placeholders
+		format, err = translated.Format(nil)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "Gesamt: %.2f (100%%)")
+
+		translated = &ARBMessage{Key: "itemCount", Message: "{Count} Artikel"}
+		_, err = translated.Format(source)
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+
+		translated = &ARBMessage{Key: "itemCount", Message: "{Count, plural, other{...}} {Name}"}
+		_, err = translated.Format(source)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"fmt"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// CatalogBuilder registers translations of decomposed source Messages into
+// a golang.org/x/text/message/catalog.Builder, checking that each of the
+// translations are valid for their source Message before insertion. The
+// source Message Format is used as the catalog key, which is what the
+// message.Printer functions expect
+type CatalogBuilder struct {
+	Builder *catalog.Builder
+}
+
+// NewCatalogBuilder constructs a new CatalogBuilder with a new
+// catalog.Builder configured with the options given
+func NewCatalogBuilder(options ...catalog.Option) (b *CatalogBuilder) {
+	b = &CatalogBuilder{
+		Builder: catalog.NewBuilder(options...),
+	}
+	return
+}
+
+// Set checks the translation of the source Message (see Message.Check) and
+// registers it with the catalog for the given language
+func (b *CatalogBuilder) Set(tag language.Tag, source *Message, translation string) (err error) {
+	if _, err = source.Check(translation); err != nil {
+		return
+	}
+	err = b.Builder.SetString(tag, source.Format, translation)
+	return
+}
+
+// SetLabelled uses Compose to convert the labelled translation of the source
+// Message into a format string and then registers it with Set
+func (b *CatalogBuilder) SetLabelled(tag language.Tag, source *Message, labelled string) (err error) {
+	var translation string
+	if translation, err = Compose(source.Format, labelled, source.Argv...); err != nil {
+		err = fmt.Errorf("%w: %q: %w", ErrInvalidTranslation, labelled, err)
+		return
+	}
+	err = b.Set(tag, source, translation)
+	return
+}
+
+// SetPlural checks the plural translation of the source Message and
+// registers it with the catalog for the given language using the
+// golang.org/x/text/feature/plural package.
+//
+// The Plural is validated using the source Message Argv, the PluralOther
+// case must be a valid translation of the source Message (see
+// Message.Check) and when there is an embedded PluralRule for the language
+// (see LookupPluralRule), all cases must be categories used by the language.
+// Cases for categories which golang.org/x/text/feature/plural never selects
+// for integers in the language (ie: the CLDR `many` case of French, which is
+// newer than the x/text plural data) are not registered, as the PluralOther
+// case is used by x/text instead
+func (b *CatalogBuilder) SetPlural(tag language.Tag, source *Message, translation *Plural) (err error) {
+	checked := &Plural{Pos: translation.Pos, Cases: translation.Cases, Argv: source.Argv}
+	if err = checked.Validate(); err != nil {
+		return
+	} else if _, err = source.Check(checked.Cases[PluralOther]); err != nil {
+		return
+	}
+
+	rule := LookupPluralRule(tag.String())
+	selector := source.Variables.Lookup(checked.Pos)
+
+	var cases []interface{}
+	for _, category := range PluralCategories {
+		if format, present := checked.Cases[category]; present {
+			if rule != nil && !rule.Has(category) {
+				err = fmt.Errorf("%w: %s case is not used by %q", ErrInvalidPlural, category, tag.String())
+				return
+			} else if category != PluralOther && !pluralSelects(tag, category) {
+				continue
+			}
+			var replaced string
+			if replaced, _, _, err = Decompose(format, source.Argv...); err != nil {
+				return
+			}
+			cases = append(cases, string(category), replaced)
+		}
+	}
+
+	err = b.Builder.Set(tag, source.Format, plural.Selectf(checked.Pos, selector.directive(false), cases...))
+	return
+}
+
+// gPluralForms maps the PluralCategory names to the x/text plural Forms
+var gPluralForms = map[PluralCategory]plural.Form{
+	PluralZero:  plural.Zero,
+	PluralOne:   plural.One,
+	PluralTwo:   plural.Two,
+	PluralFew:   plural.Few,
+	PluralMany:  plural.Many,
+	PluralOther: plural.Other,
+}
+
+// pluralSelects returns true if golang.org/x/text/feature/plural selects the
+// given category for any of a sample of integers in the language given
+func pluralSelects(tag language.Tag, category PluralCategory) (selected bool) {
+	form := gPluralForms[category]
+	for n := 0; n <= 1000; n++ {
+		if selected = plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0) == form; selected {
+			return
+		}
+	}
+	for n := 10000; n <= 1000000000; n *= 10 {
+		if selected = plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0) == form; selected {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestCatalogBuilder(t *testing.T) {
+	Convey("CatalogBuilder", t, func() {
+		source, err := NewMessage("%s has %d items", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+
+		b := NewCatalogBuilder(catalog.Fallback(language.English))
+		So(b.Set(language.German, source, "%[2]d Artikel gehören %[1]s"), ShouldEqual, nil)
+		So(b.SetLabelled(language.Dutch, source, "{Name} heeft {Count} artikelen"), ShouldEqual, nil)
+		So(b.SetPlural(language.English, source, &Plural{
+			Pos: 2,
+			Cases: map[PluralCategory]string{
+				PluralOne:   "%[1]s has one item",
+				PluralOther: "%[1]s has %[2]d items",
+			},
+		}), ShouldEqual, nil)
+
+		p := message.NewPrinter(language.German, message.Catalog(b.Builder))
+		So(p.Sprintf(source.Format, "Bob", 3), ShouldEqual, "3 Artikel gehören Bob")
+		p = message.NewPrinter(language.Dutch, message.Catalog(b.Builder))
+		So(p.Sprintf(source.Format, "Bob", 3), ShouldEqual, "Bob heeft 3 artikelen")
+		p = message.NewPrinter(language.English, message.Catalog(b.Builder))
+		So(p.Sprintf(source.Format, "Bob", 1), ShouldEqual, "Bob has one item")
+		So(p.Sprintf(source.Format, "Bob", 2), ShouldEqual, "Bob has 2 items")
+
+		err = b.Set(language.French, source, "%[2]d articles")
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+
+		err = b.SetLabelled(language.French, source, "{Name} a {Cuont} articles")
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+		So(errors.Is(err, ErrMissingLabel), ShouldBeTrue)
+
+		err = b.SetPlural(language.French, source, &Plural{
+			Pos:   2,
+			Cases: map[PluralCategory]string{PluralOther: "%[2]d articles"},
+		})
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+
+		err = b.SetPlural(language.French, source, &Plural{
+			Pos:   1,
+			Cases: map[PluralCategory]string{PluralOther: "%[1]s a %[2]d articles"},
+		})
+		So(errors.Is(err, ErrInvalidPlural), ShouldBeTrue)
+
+		So(b.SetPlural(language.French, source, &Plural{
+			Pos: 2,
+			Cases: map[PluralCategory]string{
+				PluralOne:   "%[1]s a %[2]d article",
+				PluralMany:  "%[1]s a %[2]d d'articles",
+				PluralOther: "%[1]s a %[2]d articles",
+			},
+		}), ShouldEqual, nil)
+		p = message.NewPrinter(language.French, message.Catalog(b.Builder))
+		So(p.Sprintf(source.Format, "Bob", 0), ShouldEqual, "Bob a 0 article")
+		So(p.Sprintf(source.Format, "Bob", 2), ShouldEqual, "Bob a 2 articles")
+
+		err = b.SetPlural(language.Japanese, source, &Plural{
+			Pos: 2,
+			Cases: map[PluralCategory]string{
+				PluralOne:   "%[1]s: %[2]d",
+				PluralOther: "%[1]s: %[2]d",
+			},
+		})
+		So(errors.Is(err, ErrInvalidPlural), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, `invalid plural: one case is not used by "ja"`)
+	})
+}
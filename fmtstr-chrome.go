@@ -0,0 +1,212 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ChromeFile is the structure of the Chrome extension i18n messages.json
+// files, keyed by message name
+type ChromeFile map[string]*ChromeMessage
+
+// ChromeMessage is a single Chrome i18n message, where the Message has each
+// of the placeholders replaced by its dollar-delimited name (ie: `$Name$`)
+type ChromeMessage struct {
+	Message      string                        `json:"message"`
+	Description  string                        `json:"description,omitempty"`
+	Placeholders map[string]*ChromePlaceholder `json:"placeholders,omitempty"`
+}
+
+// ChromePlaceholder is a single Chrome i18n message placeholder, where the
+// Content is the positional substitution reference (ie: `$1`)
+type ChromePlaceholder struct {
+	Content string `json:"content"`
+	Example string `json:"example,omitempty"`
+}
+
+// ReadChromeFile parses the Chrome messages.json data from the given reader
+func ReadChromeFile(r io.Reader) (file ChromeFile, err error) {
+	file = make(ChromeFile)
+	if err = json.NewDecoder(r).Decode(&file); err != nil {
+		file = nil
+	}
+	return
+}
+
+// Write writes the Chrome messages.json data to the given writer, using
+// two-space indentation
+func (f ChromeFile) Write(w io.Writer) (err error) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(f)
+	return
+}
+
+// NewChromeMessage returns the Chrome i18n message for the given source
+// Message. Each of the Variables are mapped to a placeholder: the Label is
+// the name, the Pos is the `$n` Content and the Variable.Example is the
+// Example. Chrome substitutions are always strings, so the Variable
+// formatting is not present in the Chrome message and literal dollar signs
+// are escaped as `$$`. Chrome only supports the `$1` to `$9` substitutions,
+// so an ErrNoEquivalent error is returned for argument positions above 9
+func NewChromeMessage(description string, source *Message) (message *ChromeMessage, err error) {
+	var segments Segments
+	if segments, _, err = Parse(source.Format, source.Argv...); err != nil {
+		return
+	}
+	for _, variable := range source.Variables {
+		if variable.Pos > 9 {
+			err = fmt.Errorf("%w: %q argument %d is above the Chrome $9 limit", ErrNoEquivalent, variable.Source, variable.Pos)
+			return
+		}
+	}
+
+	message = &ChromeMessage{Description: description}
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			message.Message += strings.ReplaceAll(segment.Text, "$", "$$")
+			continue
+		}
+		message.Message += "$" + segment.Variable.Label + "$"
+	}
+
+	for _, variable := range source.Variables {
+		if message.Placeholders == nil {
+			message.Placeholders = make(map[string]*ChromePlaceholder)
+		}
+		message.Placeholders[variable.Label] = &ChromePlaceholder{
+			Content: "$" + strconv.Itoa(variable.Pos),
+			Example: variable.Example(),
+		}
+	}
+	return
+}
+
+// Source returns the source Message for this Chrome message, made from the
+// placeholders. Each `$n` substitution is a %s argument at position n, with
+// the placeholder name as the argv entry
+func (m *ChromeMessage) Source() (source *Message, err error) {
+	var argv []string
+	for name, placeholder := range m.Placeholders {
+		if pos := chromeSubstitution(placeholder.Content); pos > 0 {
+			for len(argv) < pos {
+				argv = append(argv, "")
+			}
+			argv[pos-1] = name
+		}
+	}
+
+	segments := m.segments(func(pos int) *Variable {
+		return &Variable{Pos: pos, Verb: "s"}
+	})
+	source, err = NewMessage(segments.String(), argv...)
+	return
+}
+
+// Format returns the fmt format string of this Chrome message, made by
+// replacing the placeholders (and any `$n` substitutions) with the source
+// Variables at the same positions, and checks that it is a valid translation
+// of the source Message (see Message.Check). When the source Message is nil,
+// the Chrome message Source is used
+func (m *ChromeMessage) Format(source *Message) (format string, err error) {
+	if source == nil {
+		if source, err = m.Source(); err != nil {
+			return
+		}
+	}
+
+	format = m.segments(source.Variables.Lookup).String()
+	if _, err = source.Check(format); err != nil {
+		format = ""
+	}
+	return
+}
+
+// segments splits the Message into Segments, where each of the placeholders
+// and `$n` substitutions are replaced with the Variable returned by the
+// given lookup function, placeholders with literal Content are replaced with
+// their Content and `$$` is unescaped
+func (m *ChromeMessage) segments(lookup func(pos int) *Variable) (segments Segments) {
+	placeholders := make(map[string]*ChromePlaceholder)
+	for name, placeholder := range m.Placeholders {
+		placeholders[strings.ToLower(name)] = placeholder
+	}
+
+	var text strings.Builder
+	variable := func(pos int) (found bool) {
+		var v *Variable
+		if pos < 1 {
+			return false
+		} else if v = lookup(pos); v != nil {
+			if text.Len() > 0 {
+				segments = append(segments, &Segment{Text: text.String()})
+				text.Reset()
+			}
+			segments = append(segments, &Segment{Variable: v})
+		}
+		return v != nil
+	}
+
+	last := len(m.Message) - 1
+	for i := 0; i <= last; i++ {
+		if c := m.Message[i]; c != '$' || i == last {
+			text.WriteByte(c)
+			continue
+		}
+
+		if m.Message[i+1] == '$' {
+			text.WriteByte('$')
+			i += 1
+			continue
+		}
+
+		if end := strings.IndexByte(m.Message[i+1:], '$'); end > 0 {
+			if placeholder, present := placeholders[strings.ToLower(m.Message[i+1:i+1+end])]; present {
+				if pos := chromeSubstitution(placeholder.Content); pos == 0 || !variable(pos) {
+					text.WriteString(placeholder.Content)
+				}
+				i += end + 1
+				continue
+			}
+		}
+
+		// Chrome substitutions are a single digit, ie: `$12` is `$1` and `2`
+		if c := m.Message[i+1]; c >= '1' && c <= '9' && variable(int(c-'0')) {
+			i += 1
+			continue
+		}
+		text.WriteByte('$')
+	}
+
+	if text.Len() > 0 {
+		segments = append(segments, &Segment{Text: text.String()})
+	}
+	return
+}
+
+// chromeSubstitution returns the position of the `$n` substitution content
+// given, or zero if the content is not a substitution
+func chromeSubstitution(content string) (pos int) {
+	if len(content) == 2 && content[0] == '$' && isDigits(content[1:]) {
+		pos, _ = strconv.Atoi(content[1:])
+	}
+	return
+}
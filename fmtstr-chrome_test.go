@@ -0,0 +1,114 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testChromeJSON = `{
+  "itemCount": {
+    "message": "$Name$ has $Count$ items ($$)",
+    "description": "inventory summary",
+    "placeholders": {
+      "Count": {
+        "content": "$2",
+        "example": "42"
+      },
+      "Name": {
+        "content": "$1",
+        "example": "Name"
+      }
+    }
+  }
+}
+`
+
+func TestChrome(t *testing.T) {
+	source, _ := NewMessage("%s has %d items ($)", ".Name", ".Count")
+
+	Convey("NewChromeMessage and Write", t, func() {
+		message, err := NewChromeMessage("inventory summary", source)
+		So(err, ShouldEqual, nil)
+		So(message.Message, ShouldEqual, "$Name$ has $Count$ items ($$)")
+
+		var buf bytes.Buffer
+		So(ChromeFile{"itemCount": message}.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, testChromeJSON)
+
+		nine, _ := NewMessage("%[9]s")
+		message, err = NewChromeMessage("", nine)
+		So(err, ShouldEqual, nil)
+		So(message.Placeholders["Text"].Content, ShouldEqual, "$9")
+
+		ten, _ := NewMessage("%[10]s")
+		message, err = NewChromeMessage("", ten)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(message, ShouldBeNil)
+	})
+
+	Convey("ReadChromeFile", t, func() {
+		file, err := ReadChromeFile(strings.NewReader(testChromeJSON))
+		So(err, ShouldEqual, nil)
+		So(file["itemCount"].Placeholders["Name"].Content, ShouldEqual, "$1")
+
+		found, err := file["itemCount"].Source()
+		So(err, ShouldEqual, nil)
+		So(found.Format, ShouldEqual, "%s has %s items ($)")
+		So(found.Argv, ShouldResemble, []string{"Name", "Count"})
+
+		_, err = ReadChromeFile(strings.NewReader(`[]`))
+		So(err, ShouldNotEqual, nil)
+	})
+
+	Convey("Format", t, func() {
+		translated := &ChromeMessage{
+			Message: "$count$ Artikel gehören $NAME$, $$1 $1 $0 $unknown$ $3 $",
+			Placeholders: map[string]*ChromePlaceholder{
+				"Count": {Content: "$2"},
+				"Name":  {Content: "$1"},
+			},
+		}
+		format, err := translated.Format(source)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[2]d Artikel gehören %[1]s, $1 %[1]s $0 $unknown$ $3 $")
+
+		translated = &ChromeMessage{
+			Message: "$Count$ items in $shop$",
+			Placeholders: map[string]*ChromePlaceholder{
+				"Count": {Content: "$1"},
+				"Shop":  {Content: "Acme"},
+			},
+		}
+		format, err = translated.Format(nil)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%s items in Acme")
+
+		// substitutions are a single digit
+		translated = &ChromeMessage{Message: "$2 x $12"}
+		format, err = translated.Format(source)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[2]d x %[1]s2")
+
+		translated = &ChromeMessage{Message: "$1 only", Placeholders: nil}
+		_, err = translated.Format(source)
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+	})
+}
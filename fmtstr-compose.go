@@ -0,0 +1,89 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMissingLabel = errors.New("missing label")
+)
+
+// Compose is the inverse of Decompose. Compose takes the original `source`
+// format string and `argv` list, along with a `labelled` translation of the
+// source (as produced by Decompose and then edited by a translator) and
+// returns the fmt format string for the translation with all labels mapped
+// back to their source argument positions.
+//
+// Translators are free to reorder the labels as their language requires and
+// Compose will only emit explicit argument indexes where the order differs
+// from the implicit argument order. For example: given a source of
+// `%s has %d items` with argv of `.Name` and `.Count`, a translation of
+// `{Count} items belong to {Name}` is composed as
+// `%[2]d items belong to %[1]s`, while a translation of
+// `{Name} owns {Count} items` is composed as `%s owns %d items`.
+//
+// Curly-braced text that is not one of the source labels is left as-is and
+// Compose returns an ErrMissingLabel error if any of the source labels are
+// not present in the translation.
+func Compose(source, labelled string, argv ...string) (format string, err error) {
+	var variables Variables
+	if _, variables, err = Parse(source, argv...); err != nil {
+		return
+	}
+
+	lookup := make(map[string]*Variable)
+	for _, variable := range variables {
+		lookup[variable.Label] = variable
+	}
+
+	var segments Segments
+	used := make(map[int]struct{})
+
+	var text strings.Builder
+	for i := 0; i < len(labelled); i++ {
+		if labelled[i] == '{' {
+			if end := strings.IndexByte(labelled[i+1:], '}'); end > -1 {
+				if variable, present := lookup[labelled[i+1:i+1+end]]; present {
+					if text.Len() > 0 {
+						segments = append(segments, newTextSegment(text.String()))
+						text.Reset()
+					}
+					segments = append(segments, &Segment{Variable: variable})
+					used[variable.Pos] = struct{}{}
+					i += end + 1
+					continue
+				}
+			}
+		}
+		text.WriteByte(labelled[i])
+	}
+	if text.Len() > 0 {
+		segments = append(segments, newTextSegment(text.String()))
+	}
+
+	for _, variable := range variables {
+		if _, present := used[variable.Pos]; !present {
+			err = fmt.Errorf("%w: {%s}", ErrMissingLabel, variable.Label)
+			return
+		}
+	}
+
+	format = segments.String()
+	return
+}
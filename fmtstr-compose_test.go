@@ -0,0 +1,63 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompose(t *testing.T) {
+	Convey("Compose", t, func() {
+
+		format, err := Compose("", "")
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "")
+
+		format, err = Compose("%s has %d items", "{Name} owns {Count} items", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%s owns %d items")
+
+		format, err = Compose("%s has %d items", "{Count} items belong to {Name}", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[2]d items belong to %[1]s")
+		So(fmt.Sprintf(format, "Bob", 10), ShouldEqual, "10 items belong to Bob")
+
+		format, err = Compose("%s, %s and %s", "{C} {A} {B}", ".A", ".B", ".C")
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[3]s %[1]s %s")
+
+		format, err = Compose("100%% of %-5.2f", "{Float} is 100%% {Other}")
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%-5.2f is 100%% {Other}")
+
+		format, err = Compose("%[1]d and %[1]d", "{Num}, {Num}, {Num}")
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%d, %[1]d, %[1]d")
+
+		format, err = Compose("%s has %d items", "{Name} owns {Cuont} items", ".Name", ".Count")
+		So(err, ShouldNotEqual, nil)
+		So(errors.Is(err, ErrMissingLabel), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "missing label: {Count}")
+		So(format, ShouldEqual, "")
+
+		format, err = Compose("%[1]d %[1]s", "{Num}")
+		So(err, ShouldNotEqual, nil)
+		So(format, ShouldEqual, "")
+	})
+}
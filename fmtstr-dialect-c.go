@@ -0,0 +1,251 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrMixedPositions = errors.New("mixed positional and sequential arguments")
+)
+
+var (
+	// CDialect is the Dialect of C's printf family of functions, as used by
+	// GNU gettext's c-format strings, including the length modifiers (ie:
+	// `%lld` and `%zu`), the `%n` conversion and the POSIX positional
+	// arguments (ie: `%1$s`).
+	//
+	// C conversions are parsed into their equivalent Go verbs: `%i` and `%u`
+	// become `d` with the Variable.Conversion kept for formatting back to C,
+	// `%a` and `%A` become `x` and `X` with a Variable.Type of "float" and
+	// the `%n` conversion is kept as an `n` Verb which has no Go equivalent.
+	// When formatting Go Variables, the `%v` verb becomes `%s` and the `%b`,
+	// `%O`, `%q`, `%t`, `%T` and `%U` verbs have no C equivalent.
+	CDialect Dialect = cPrintfDialect{}
+)
+
+var gCLengthModifiers = []string{"hh", "ll", "h", "l", "j", "z", "t", "L", "q"}
+
+type cPrintfDialect struct{}
+
+func (d cPrintfDialect) Name() string {
+	return "c"
+}
+
+func (d cPrintfDialect) Parse(format string, argv ...string) (segments Segments, variables Variables, err error) {
+	var positional, sequential bool
+	next, literal := 1, 0
+	last := len(format) - 1
+
+	for i := 0; i <= last; i++ {
+		if format[i] != '%' {
+			continue
+		} else if i < last && format[i+1] == '%' {
+			// literal percent
+			i += 1
+			continue
+		}
+
+		start := i
+		state := &cState{pos: next}
+
+		j := i + 1
+		// POSIX positional argument
+		k := j
+		for k <= last && unicode.IsDigit(rune(format[k])) {
+			k += 1
+		}
+		if k > j && k <= last && format[k] == '$' {
+			if state.pos, _ = strconv.Atoi(format[j:k]); state.pos < 1 {
+				err = invalidFormat(format, start, k)
+				return
+			}
+			positional = true
+			j = k + 1
+		} else {
+			sequential = true
+		}
+		if positional && sequential {
+			err = fmt.Errorf("%w at: %v", ErrMixedPositions, format[start:j])
+			return
+		}
+
+		if j, err = scanPrintfFlags(state, format, start, j); err != nil {
+			return
+		}
+
+		// length modifier
+		for _, length := range gCLengthModifiers {
+			if strings.HasPrefix(format[j:], length) {
+				state.length = length
+				j += len(length)
+				break
+			}
+		}
+
+		if j > last {
+			err = invalidFormat(format, start, j)
+			return
+		}
+
+		switch c := format[j]; c {
+		case 'd':
+			state.verb = "d"
+		case 'i', 'u':
+			state.verb, state.conversion = "d", string(c)
+		case 'o', 'x', 'X', 'e', 'E', 'f', 'F', 'g', 'G', 'c', 's', 'p', 'n':
+			state.verb = Verb(c)
+		case 'a':
+			state.verb, state.valueType = "x", "float"
+		case 'A':
+			state.verb, state.valueType = "X", "float"
+		default:
+			err = invalidFormat(format, start, j)
+			return
+		}
+
+		state.source = format[start : j+1]
+		if start > literal {
+			segments = append(segments, newTextSegment(format[literal:start]))
+		}
+		segments = append(segments, &Segment{Variable: state.make(argv)})
+
+		literal = j + 1
+		next = state.pos + 1
+		i = j
+	}
+
+	if literal <= last {
+		segments = append(segments, newTextSegment(format[literal:]))
+	}
+
+	if variables, err = segments.Variables().resolve(); err != nil {
+		segments = nil
+	}
+	return
+}
+
+func (d cPrintfDialect) Format(segments Segments, explicit bool) (format string, err error) {
+	if !explicit {
+		// C does not allow mixing positional and sequential arguments
+		for idx, variable := range segments.Variables() {
+			if variable.Pos != idx+1 {
+				explicit = true
+				break
+			}
+		}
+	}
+
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			format += escapeText(segment.Text)
+			continue
+		}
+		var directive string
+		if directive, err = d.directive(segment.Variable, explicit); err != nil {
+			format = ""
+			return
+		}
+		format += directive
+	}
+	return
+}
+
+func (d cPrintfDialect) directive(v *Variable, explicit bool) (value string, err error) {
+	var conversion string
+	switch v.Verb {
+	case "d":
+		if conversion = "d"; v.Conversion != "" {
+			conversion = v.Conversion
+		}
+	case "o", "e", "E", "f", "F", "g", "G", "c", "s", "p", "n":
+		conversion = v.Verb.String()
+	case "x":
+		if conversion = "x"; v.Type == "float" {
+			conversion = "a"
+		}
+	case "X":
+		if conversion = "X"; v.Type == "float" {
+			conversion = "A"
+		}
+	case "v":
+		conversion = "s"
+	default:
+		err = fmt.Errorf("%w: %q is not a %s conversion", ErrNoEquivalent, v.Source, d.Name())
+		return
+	}
+
+	value = "%"
+	if explicit {
+		value += strconv.Itoa(v.Pos) + "$"
+	}
+	value += v.flags()
+	value += v.Length + conversion
+	return
+}
+
+// scanPrintfFlags updates the state with the printf-style flags, width and
+// precision found in the format string, starting at the index given and
+// returning the index of the first rune that is not a flag, width or
+// precision
+func scanPrintfFlags(state *cState, format string, start, index int) (next int, err error) {
+	last := len(format) - 1
+
+flags:
+	for next = index; next <= last; next++ {
+		switch format[next] {
+		case '-', '+', '#', ' ':
+			state.updatePMHS(rune(format[next]), format[next:next+1])
+		case '0':
+			state.zero = true
+		case '\'':
+			err = fmt.Errorf("%w: thousands grouping at: %v", ErrNoEquivalent, format[start:next+1])
+			return
+		default:
+			break flags
+		}
+	}
+
+	for ; next <= last; next++ {
+		if r := rune(format[next]); r == '*' {
+			err = ErrPosArgNotImpl
+			return
+		} else if r == '.' && !state.decimal {
+			state.decimal = true
+		} else if !unicode.IsDigit(r) {
+			break
+		} else if state.decimal {
+			state.precision += string(r)
+		} else {
+			state.width += string(r)
+		}
+	}
+	return
+}
+
+// invalidFormat returns the standard invalid format error for the portion of
+// the format string from start up to and including end
+func invalidFormat(format string, start, end int) (err error) {
+	if last := len(format) - 1; end > last {
+		end = last
+	}
+	err = fmt.Errorf("invalid format at: %v", format[start:end+1])
+	return
+}
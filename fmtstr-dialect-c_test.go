@@ -0,0 +1,93 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCDialect(t *testing.T) {
+	Convey("Parse", t, func() {
+		segments, variables, err := CDialect.Parse("")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 0)
+		So(len(variables), ShouldEqual, 0)
+
+		segments, variables, err = CDialect.Parse("%-5s: %+08.3f, %zu, %hhx and %A%%", ".Name")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 10)
+		So(len(variables), ShouldEqual, 5)
+		So(variables[0].Label, ShouldEqual, "Name")
+		So(variables[0].Has(ModMinus), ShouldBeTrue)
+		So(variables[0].Width, ShouldEqual, 5)
+		So(variables[1].Verb, ShouldEqual, Verb("f"))
+		So(variables[1].Has(ModPlus|ModZeroPad|ModDecimal), ShouldBeTrue)
+		So(variables[1].Width, ShouldEqual, 8)
+		So(variables[1].Precision, ShouldEqual, 3)
+		So(variables[2].Verb, ShouldEqual, Verb("d"))
+		So(variables[2].Length, ShouldEqual, "z")
+		So(variables[2].Conversion, ShouldEqual, "u")
+		So(variables[3].Length, ShouldEqual, "hh")
+		So(variables[4].Verb, ShouldEqual, Verb("X"))
+		So(variables[4].Type, ShouldEqual, "float")
+		So(variables[4].Label, ShouldEqual, "Float1")
+		So(segments[9].Text, ShouldEqual, "%")
+
+		format, err := CDialect.Format(segments, false)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%-5s: %+08.3f, %zu, %hhx and %A%%")
+
+		converted, err := Convert("%zu %u %i %d", CDialect, CDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%zu %u %i %d")
+		converted, err = Convert("%zu %i", CDialect, GoDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%d %d")
+
+		segments, variables, err = CDialect.Parse("%2$s %1$s %2$s")
+		So(err, ShouldEqual, nil)
+		So(len(variables), ShouldEqual, 2)
+		format, err = CDialect.Format(segments, false)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%2$s %1$s %2$s")
+
+		_, _, err = CDialect.Parse("%1$s %s")
+		So(errors.Is(err, ErrMixedPositions), ShouldBeTrue)
+
+		_, _, err = CDialect.Parse("%'d")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = CDialect.Parse("%*d")
+		So(err, ShouldEqual, ErrPosArgNotImpl)
+
+		_, _, err = CDialect.Parse("%0$d")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: %0$")
+
+		_, _, err = CDialect.Parse("%lk")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: %lk")
+
+		_, _, err = CDialect.Parse("trailing %5")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: %5")
+
+		_, _, err = CDialect.Parse("%1$d %1$s")
+		So(err, ShouldNotEqual, nil)
+	})
+}
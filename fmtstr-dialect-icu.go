@@ -0,0 +1,273 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ICUDialect is the Dialect of ICU MessageFormat strings, limited to the
+	// simple argument subset: `{name}`, `{name, number}`,
+	// `{name, number, integer}` and `{name, number, ::skeleton}` where the
+	// skeleton is made of a fixed fraction precision (ie: `.00`) and or the
+	// `sign-always` option.
+	//
+	// Go Variables are formatted as named arguments using their Variable.Key,
+	// with num Variables becoming `{name, number, integer}` and float
+	// Variables becoming `{name, number}` (or `{name, number, ::.00}` when a
+	// precision is present). The ICU `plural`, `select`, `selectordinal`,
+	// `date`, `time` and other complex arguments have no fmt equivalent and
+	// neither do the fmt width and padding flags.
+	//
+	// When parsing, named arguments are labelled by their names and are
+	// positioned after all numbered arguments, in the order of their first
+	// appearance, unless the name matches the label of an argv entry after
+	// the numbered arguments, in which case the argv position is used.
+	ICUDialect Dialect = cICUDialect{}
+)
+
+type cICUDialect struct{}
+
+func (d cICUDialect) Name() string {
+	return "icu"
+}
+
+func (d cICUDialect) Parse(format string, argv ...string) (segments Segments, variables Variables, err error) {
+	var named []*Variable
+	highest := 0
+	last := len(format) - 1
+
+	var text strings.Builder
+	for i := 0; i <= last; i++ {
+		switch c := format[i]; c {
+
+		case '\'':
+			if i < last && format[i+1] == '\'' {
+				// escaped apostrophe
+				text.WriteByte('\'')
+				i += 1
+			} else if i < last && strings.IndexByte("{}#|", format[i+1]) > -1 {
+				// quoted literal text
+				for i += 1; i <= last; i++ {
+					if format[i] == '\'' {
+						if i < last && format[i+1] == '\'' {
+							text.WriteByte('\'')
+							i += 1
+							continue
+						}
+						break
+					}
+					text.WriteByte(format[i])
+				}
+			} else {
+				text.WriteByte(c)
+			}
+
+		case '}':
+			err = invalidFormat(format, i, i)
+			return
+
+		case '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				err = invalidFormat(format, i, last)
+				return
+			}
+			var state *cState
+			if state, err = d.parseArgument(format[i:], end); err != nil {
+				return
+			}
+			if state.name == "" && state.pos > highest {
+				highest = state.pos
+			}
+
+			if text.Len() > 0 {
+				segments = append(segments, &Segment{Text: text.String()})
+				text.Reset()
+			}
+			variable := state.make(argv)
+			if state.name != "" {
+				named = append(named, variable)
+			}
+			segments = append(segments, &Segment{Variable: variable})
+			i += end
+
+		default:
+			text.WriteByte(c)
+		}
+	}
+
+	if text.Len() > 0 {
+		segments = append(segments, &Segment{Text: text.String()})
+	}
+
+	// named arguments are positioned after all of the numbered arguments
+	positionNamed(named, argv, highest)
+
+	if variables, err = segments.Variables().resolve(); err != nil {
+		segments = nil
+	}
+	return
+}
+
+// parseArgument parses the ICU argument at the start of the format given,
+// where end is the index of the first closing brace
+func (d cICUDialect) parseArgument(format string, end int) (state *cState, err error) {
+	source := format[:end+1]
+	parts := strings.Split(source[1:end], ",")
+	for idx := range parts {
+		parts[idx] = strings.TrimSpace(parts[idx])
+	}
+
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "plural", "select", "selectordinal":
+			// these contain nested messages, report the complete argument
+			depth := 0
+			for idx := 0; idx < len(format); idx++ {
+				if format[idx] == '{' {
+					depth += 1
+				} else if format[idx] == '}' {
+					if depth -= 1; depth == 0 {
+						source = format[:idx+1]
+						break
+					}
+				}
+			}
+			err = fmt.Errorf("%w: %s argument at: %v", ErrNoEquivalent, parts[1], source)
+			return
+		}
+	}
+
+	state = &cState{source: source, verb: "v"}
+	if name := parts[0]; name == "" || strings.ContainsAny(name, " \t\r\n'#|") {
+		err = invalidFormat(source, 0, end)
+		return
+	} else if isDigits(name) {
+		state.pos, _ = strconv.Atoi(name)
+		state.pos += 1
+	} else {
+		state.name = name
+	}
+
+	switch len(parts) {
+	case 1:
+		// simple argument
+	case 2, 3:
+		if parts[1] != "number" {
+			err = fmt.Errorf("%w: %s argument at: %v", ErrNoEquivalent, parts[1], source)
+			return
+		}
+		if len(parts) == 2 {
+			break
+		}
+		switch style := parts[2]; {
+		case style == "integer":
+			state.verb = "d"
+		case strings.HasPrefix(style, "::"):
+			state.verb = "f"
+			for _, token := range strings.Fields(style[2:]) {
+				if token == "sign-always" || token == "+!" {
+					state.plus = true
+				} else if strings.HasPrefix(token, ".") && strings.Trim(token[1:], "0") == "" {
+					state.decimal = true
+					state.precision = strconv.Itoa(len(token) - 1)
+				} else {
+					err = fmt.Errorf("%w: number skeleton %q at: %v", ErrNoEquivalent, token, source)
+					return
+				}
+			}
+			if !state.decimal {
+				state.verb = "v"
+				if state.plus {
+					state.verb = "d"
+				}
+			}
+		default:
+			err = fmt.Errorf("%w: number style %q at: %v", ErrNoEquivalent, style, source)
+			return
+		}
+	default:
+		err = invalidFormat(source, 0, end)
+		return
+	}
+	return
+}
+
+func (d cICUDialect) Format(segments Segments, explicit bool) (format string, err error) {
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			format += d.escape(segment.Text)
+			continue
+		}
+		var argument string
+		if argument, err = d.argument(segment.Variable); err != nil {
+			format = ""
+			return
+		}
+		format += argument
+	}
+	return
+}
+
+func (d cICUDialect) argument(v *Variable) (value string, err error) {
+	if v.Width > 0 || v.Has(ModMinus) || v.Has(ModZeroPad) || v.Has(ModSpace) || v.Has(ModHash) {
+		err = fmt.Errorf("%w: %q has padding flags", ErrNoEquivalent, v.Source)
+		return
+	}
+
+	var skeleton []string
+	if v.Has(ModPlus) {
+		skeleton = append(skeleton, "sign-always")
+	}
+
+	value = "{" + v.Key()
+	switch v.Verb {
+	case "d":
+		if len(skeleton) > 0 {
+			value += ", number, ::" + strings.Join(skeleton, " ")
+		} else {
+			value += ", number, integer"
+		}
+	case "f", "F", "g", "G":
+		if v.Has(ModDecimal) {
+			skeleton = append(skeleton, "."+strings.Repeat("0", v.Precision))
+		}
+		value += ", number"
+		if len(skeleton) > 0 {
+			value += ", ::" + strings.Join(skeleton, " ")
+		}
+	case "s", "v", "t":
+		if len(skeleton) > 0 {
+			err = fmt.Errorf("%w: %q has the plus flag", ErrNoEquivalent, v.Source)
+			return
+		}
+	default:
+		err = fmt.Errorf("%w: %q is not an %s argument", ErrNoEquivalent, v.Source, d.Name())
+		return
+	}
+	value += "}"
+	return
+}
+
+func (d cICUDialect) escape(text string) (escaped string) {
+	escaped = strings.ReplaceAll(text, "'", "''")
+	escaped = strings.ReplaceAll(escaped, "{", "'{'")
+	escaped = strings.ReplaceAll(escaped, "}", "'}'")
+	return
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestICUDialect(t *testing.T) {
+	Convey("Format", t, func() {
+		converted, err := Convert("%s has %d items", GoDialect, ICUDialect, ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{name} has {count, number, integer} items")
+
+		converted, err = Convert("%[2]s owes %+.2[1]f {at} %[3]v's", GoDialect, ICUDialect, ".Amount", ".UserName")
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{user_name} owes {amount, number, ::sign-always .00} '{'at'}' {var}''s")
+
+		converted, err = Convert("%5d", GoDialect, ICUDialect)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(converted, ShouldEqual, "")
+
+		converted, err = Convert("%x", GoDialect, ICUDialect)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(converted, ShouldEqual, "")
+	})
+
+	Convey("Parse", t, func() {
+		segments, variables, err := ICUDialect.Parse("{user_name} owes {amount, number, ::sign-always .00} '{'at'}' {0}''s", ".First")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 6)
+		So(segments[3].Text, ShouldEqual, " {at} ")
+		So(segments[5].Text, ShouldEqual, "'s")
+		So(len(variables), ShouldEqual, 3)
+		So(variables[0].Label, ShouldEqual, "First")
+		So(variables[1].Label, ShouldEqual, "UserName")
+		So(variables[1].Name, ShouldEqual, "user_name")
+		So(variables[2].Label, ShouldEqual, "Amount")
+		So(variables[2].Verb, ShouldEqual, Verb("f"))
+		So(variables[2].Precision, ShouldEqual, 2)
+		So(variables[2].Has(ModPlus), ShouldBeTrue)
+
+		converted, err := Convert("{name} has {count, number, integer} items, it's {count, number}", ICUDialect, GoDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%v has %d items, it's %[2]v")
+
+		// named arguments are positioned by their argv labels
+		converted, err = Convert("%[2]d items for %[1]s", GoDialect, ICUDialect, ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{count, number, integer} items for {name}")
+		converted, err = Convert(converted, ICUDialect, GoDialect, ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%[2]d items for %[1]v")
+
+		_, _, err = ICUDialect.Parse("{count, plural, one {# item} other {# items}}!")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "no equivalent directive: plural argument at: {count, plural, one {# item} other {# items}}")
+
+		_, _, err = ICUDialect.Parse("{gender, select, female {She} other {They}}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = ICUDialect.Parse("{when, date, short}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = ICUDialect.Parse("{n, number, percent}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = ICUDialect.Parse("{n, number, ::compact-short}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = ICUDialect.Parse("{}")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: {}")
+
+		_, _, err = ICUDialect.Parse("{name")
+		So(err, ShouldNotEqual, nil)
+
+		_, _, err = ICUDialect.Parse("name}")
+		So(err, ShouldNotEqual, nil)
+	})
+}
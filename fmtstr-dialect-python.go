@@ -0,0 +1,516 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// PythonDialect is the Dialect of Python's printf-style `%` operator,
+	// as used by GNU gettext's python-format strings, including the named
+	// mapping keys (ie: `%(name)s`).
+	//
+	// Named arguments are labelled by their names and are positioned in the
+	// order of their first appearance, unless the name matches the label of
+	// an argv entry, in which case the argv position is used. Python
+	// conversions are parsed into their equivalent Go verbs: `%i` and `%u`
+	// become `d`, `%#o` becomes `O`, `%r` becomes `q` and `%a` becomes `+q`.
+	// When formatting Go Variables, the `%v` and `%t` verbs become `%s` and
+	// any format that is not in the implicit argument order uses named
+	// mapping keys (see Variable.Key) because Python has no other way to
+	// reorder arguments.
+	PythonDialect Dialect = cPythonDialect{}
+
+	// PythonBraceDialect is the Dialect of Python's str.format method, as used
+	// by GNU gettext's python-brace-format strings, for example: `{}`,
+	// `{0:>10.2f}` and `{name!r}`.
+	//
+	// Named fields are labelled by their names and are positioned after all
+	// numbered fields, in the order of their first appearance, unless the
+	// name matches the label of an argv entry after the numbered fields, in
+	// which case the argv position is used. Python format specs are parsed
+	// into the equivalent Go modifiers, width, precision and verb, with the
+	// `!r` conversion becoming `q` and `!a` becoming `+q`. Python format
+	// specs with a fill character, centered or sign-aware alignment, the `z`
+	// option or a grouping option have no Go equivalent.
+	PythonBraceDialect Dialect = cPythonBraceDialect{}
+)
+
+type cPythonDialect struct{}
+
+func (d cPythonDialect) Name() string {
+	return "python"
+}
+
+func (d cPythonDialect) Parse(format string, argv ...string) (segments Segments, variables Variables, err error) {
+	var named []*Variable
+	var sequential bool
+	next, literal := 1, 0
+	last := len(format) - 1
+
+	for i := 0; i <= last; i++ {
+		if format[i] != '%' {
+			continue
+		} else if i < last && format[i+1] == '%' {
+			// literal percent
+			i += 1
+			continue
+		}
+
+		start := i
+		state := &cState{}
+
+		j := i + 1
+		if j <= last && format[j] == '(' {
+			end := strings.IndexByte(format[j:], ')')
+			if end < 0 {
+				err = invalidFormat(format, start, last)
+				return
+			}
+			state.name = format[j+1 : j+end]
+			j += end + 1
+		} else {
+			state.pos = next
+			sequential = true
+			next += 1
+		}
+		if state.name != "" && sequential || state.name == "" && len(named) > 0 {
+			err = fmt.Errorf("%w at: %v", ErrMixedPositions, format[start:j])
+			return
+		}
+
+		if j, err = scanPrintfFlags(state, format, start, j); err != nil {
+			return
+		}
+
+		if j <= last && strings.IndexByte("hlL", format[j]) > -1 {
+			// length modifiers are accepted and ignored by Python
+			j += 1
+		}
+
+		if j > last {
+			err = invalidFormat(format, start, j)
+			return
+		}
+
+		switch c := format[j]; c {
+		case 'd', 'i', 'u':
+			state.verb = "d"
+		case 'o':
+			if state.verb = "o"; state.hash {
+				state.verb, state.hash = "O", false
+			}
+		case 'x', 'X', 'e', 'E', 'f', 'F', 'g', 'G', 'c', 's':
+			state.verb = Verb(c)
+		case 'r':
+			state.verb = "q"
+		case 'a':
+			state.verb, state.plus = "q", true
+		default:
+			err = invalidFormat(format, start, j)
+			return
+		}
+
+		state.source = format[start : j+1]
+		if start > literal {
+			segments = append(segments, newTextSegment(format[literal:start]))
+		}
+		variable := state.make(argv)
+		if state.name != "" {
+			named = append(named, variable)
+		}
+		segments = append(segments, &Segment{Variable: variable})
+
+		literal = j + 1
+		i = j
+	}
+
+	if literal <= last {
+		segments = append(segments, newTextSegment(format[literal:]))
+	}
+	positionNamed(named, argv, 0)
+
+	if variables, err = segments.Variables().resolve(); err != nil {
+		segments = nil
+	}
+	return
+}
+
+func (d cPythonDialect) Format(segments Segments, explicit bool) (format string, err error) {
+	named := explicit
+	for idx, variable := range segments.Variables() {
+		if variable.Name != "" || variable.Pos != idx+1 {
+			named = true
+			break
+		}
+	}
+
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			format += escapeText(segment.Text)
+			continue
+		}
+		var directive string
+		if directive, err = d.directive(segment.Variable, named); err != nil {
+			format = ""
+			return
+		}
+		format += directive
+	}
+	return
+}
+
+func (d cPythonDialect) directive(v *Variable, named bool) (value string, err error) {
+	var conversion string
+	modified := *v
+	switch v.Verb {
+	case "d", "o", "x", "X", "e", "E", "f", "F", "g", "G", "c", "s":
+		conversion = v.Verb.String()
+	case "O":
+		conversion = "o"
+		modified.Modifiers |= ModHash
+	case "q":
+		if conversion = "r"; v.Has(ModPlus) {
+			conversion = "a"
+			modified.Modifiers &^= ModPlus
+		}
+	case "v", "t":
+		conversion = "s"
+	default:
+		err = fmt.Errorf("%w: %q is not a %s conversion", ErrNoEquivalent, v.Source, d.Name())
+		return
+	}
+
+	value = "%"
+	if named {
+		value += "(" + v.Key() + ")"
+	}
+	value += modified.flags() + conversion
+	return
+}
+
+type cPythonBraceDialect struct{}
+
+func (d cPythonBraceDialect) Name() string {
+	return "python-brace"
+}
+
+func (d cPythonBraceDialect) Parse(format string, argv ...string) (segments Segments, variables Variables, err error) {
+	var auto, manual bool
+	var named []*Variable
+	next, highest := 1, 0
+	last := len(format) - 1
+
+	var text strings.Builder
+	for i := 0; i <= last; i++ {
+		c := format[i]
+		if c == '}' {
+			if i < last && format[i+1] == '}' {
+				text.WriteByte('}')
+				i += 1
+				continue
+			}
+			err = invalidFormat(format, i, i)
+			return
+		} else if c != '{' {
+			text.WriteByte(c)
+			continue
+		} else if i < last && format[i+1] == '{' {
+			text.WriteByte('{')
+			i += 1
+			continue
+		}
+
+		end := strings.IndexByte(format[i:], '}')
+		if end < 0 {
+			err = invalidFormat(format, i, last)
+			return
+		}
+		source := format[i : i+end+1]
+		field := source[1 : len(source)-1]
+		if strings.ContainsRune(field, '{') {
+			// nested replacement fields are the str.format equivalent of
+			// the explicit argument indexes for width and precision
+			err = ErrPosArgNotImpl
+			return
+		}
+
+		state := &cState{source: source}
+
+		var conversion, spec string
+		name := field
+		if idx := strings.IndexAny(field, "!:"); idx > -1 {
+			name = field[:idx]
+			if field[idx] == '!' {
+				if conversion = field[idx+1:]; len(conversion) > 1 && conversion[1] == ':' {
+					spec = conversion[2:]
+					conversion = conversion[:1]
+				}
+				if len(conversion) != 1 || strings.IndexByte("rsa", conversion[0]) < 0 {
+					err = invalidFormat(format, i, i+end)
+					return
+				}
+			} else {
+				spec = field[idx+1:]
+			}
+		}
+
+		if name == "" {
+			auto = true
+			state.pos = next
+			next += 1
+		} else if isDigits(name) {
+			manual = true
+			state.pos, _ = strconv.Atoi(name)
+			state.pos += 1
+		} else {
+			state.name = name
+		}
+		if auto && manual {
+			err = fmt.Errorf("%w at: %v", ErrMixedPositions, source)
+			return
+		} else if state.pos > highest {
+			highest = state.pos
+		}
+
+		if err = d.parseSpec(state, conversion, spec); err != nil {
+			return
+		}
+
+		if text.Len() > 0 {
+			segments = append(segments, &Segment{Text: text.String()})
+			text.Reset()
+		}
+		variable := state.make(argv)
+		if state.name != "" {
+			named = append(named, variable)
+		}
+		segments = append(segments, &Segment{Variable: variable})
+		i += end
+	}
+
+	if text.Len() > 0 {
+		segments = append(segments, &Segment{Text: text.String()})
+	}
+
+	// named fields are positioned after all of the numbered fields
+	positionNamed(named, argv, highest)
+
+	if variables, err = segments.Variables().resolve(); err != nil {
+		segments = nil
+	}
+	return
+}
+
+func (d cPythonBraceDialect) parseSpec(state *cState, conversion, spec string) (err error) {
+	invalid := fmt.Errorf("invalid format at: %v", state.source)
+
+	idx := 0
+	var align byte
+	if len(spec) >= 2 && strings.IndexByte("<>=^", spec[1]) > -1 {
+		if spec[0] != ' ' {
+			err = fmt.Errorf("%w: fill character at: %v", ErrNoEquivalent, state.source)
+			return
+		}
+		align, idx = spec[1], 2
+	} else if len(spec) >= 1 && strings.IndexByte("<>=^", spec[0]) > -1 {
+		align, idx = spec[0], 1
+	}
+	switch align {
+	case '<':
+		state.minus = true
+	case '=', '^':
+		err = fmt.Errorf("%w: alignment at: %v", ErrNoEquivalent, state.source)
+		return
+	}
+
+	if idx < len(spec) {
+		switch spec[idx] {
+		case '+':
+			state.plus = true
+			idx += 1
+		case ' ':
+			state.space = true
+			idx += 1
+		case '-':
+			// the default sign option
+			idx += 1
+		}
+	}
+	if idx < len(spec) && spec[idx] == 'z' {
+		err = fmt.Errorf("%w: z option at: %v", ErrNoEquivalent, state.source)
+		return
+	}
+	if idx < len(spec) && spec[idx] == '#' {
+		state.hash = true
+		idx += 1
+	}
+	if idx < len(spec) && spec[idx] == '0' {
+		state.zero = true
+		idx += 1
+	}
+	for ; idx < len(spec) && unicode.IsDigit(rune(spec[idx])); idx++ {
+		state.width += spec[idx : idx+1]
+	}
+	if idx < len(spec) && (spec[idx] == ',' || spec[idx] == '_') {
+		err = fmt.Errorf("%w: grouping option at: %v", ErrNoEquivalent, state.source)
+		return
+	}
+	if idx < len(spec) && spec[idx] == '.' {
+		state.decimal = true
+		for idx += 1; idx < len(spec) && unicode.IsDigit(rune(spec[idx])); idx++ {
+			state.precision += spec[idx : idx+1]
+		}
+	}
+
+	switch kind := spec[idx:]; kind {
+	case "":
+		switch conversion {
+		case "r":
+			state.verb = "q"
+		case "a":
+			state.verb, state.plus = "q", true
+		case "s":
+			state.verb = "s"
+		default:
+			state.verb = "v"
+		}
+	case "b", "c", "d", "e", "E", "f", "F", "g", "G", "s", "x", "X":
+		state.verb = Verb(kind)
+	case "o":
+		if state.verb = "o"; state.hash {
+			state.verb, state.hash = "O", false
+		}
+	case "n", "%":
+		err = fmt.Errorf("%w: %q presentation type at: %v", ErrNoEquivalent, kind, state.source)
+		return
+	default:
+		err = invalid
+		return
+	}
+
+	if conversion != "" && state.verb != "q" && state.verb != "s" {
+		// conversions produce strings, which only support the s type
+		err = invalid
+		return
+	}
+
+	if state.verb == "s" && state.width != "" && align == 0 {
+		// python left-aligns strings by default
+		state.minus = true
+	}
+	return
+}
+
+func (d cPythonBraceDialect) Format(segments Segments, explicit bool) (format string, err error) {
+	var unnamed int
+	numbered := explicit
+	for _, variable := range segments.Variables() {
+		if variable.Name == "" {
+			if unnamed += 1; variable.Pos != unnamed {
+				numbered = true
+			}
+		}
+	}
+
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			format += strings.NewReplacer("{", "{{", "}", "}}").Replace(segment.Text)
+			continue
+		}
+		var field string
+		if field, err = d.field(segment.Variable, numbered); err != nil {
+			format = ""
+			return
+		}
+		format += field
+	}
+	return
+}
+
+func (d cPythonBraceDialect) field(v *Variable, numbered bool) (value string, err error) {
+	var conversion, kind string
+	hash := v.Has(ModHash)
+	plus := v.Has(ModPlus)
+	switch v.Verb {
+	case "b", "c", "d", "e", "E", "f", "F", "g", "G", "o", "s", "x", "X":
+		kind = v.Verb.String()
+	case "O":
+		kind, hash = "o", true
+	case "q":
+		if conversion = "!r"; plus {
+			conversion, plus = "!a", false
+		}
+	case "v", "t":
+	default:
+		err = fmt.Errorf("%w: %q is not a %s conversion", ErrNoEquivalent, v.Source, d.Name())
+		return
+	}
+
+	var spec string
+	if v.Has(ModMinus) {
+		spec += "<"
+	} else if v.Width > 0 && (kind == "s" || conversion != "") {
+		// go right-aligns strings by default
+		spec += ">"
+	}
+	if plus {
+		spec += "+"
+	} else if v.Has(ModSpace) {
+		spec += " "
+	}
+	if hash {
+		spec += "#"
+	}
+	if v.Has(ModZeroPad) {
+		spec += "0"
+	}
+	if v.Width > 0 {
+		spec += strconv.Itoa(v.Width)
+	}
+	if v.Has(ModDecimal) {
+		spec += "." + strconv.Itoa(v.Precision)
+	}
+	if kind != "s" || spec != "" {
+		spec += kind
+	}
+
+	value = "{"
+	if v.Name != "" {
+		value += v.Name
+	} else if numbered {
+		value += strconv.Itoa(v.Pos - 1)
+	}
+	value += conversion
+	if spec != "" {
+		value += ":" + spec
+	}
+	value += "}"
+	return
+}
+
+func isDigits(value string) (digits bool) {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return
+		}
+	}
+	digits = value != ""
+	return
+}
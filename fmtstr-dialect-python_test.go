@@ -0,0 +1,207 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPythonDialect(t *testing.T) {
+	Convey("Parse", t, func() {
+		segments, variables, err := PythonDialect.Parse("%(user_name)s has %(count)05d items, %(user_name)r!")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 6)
+		So(len(variables), ShouldEqual, 2)
+		So(variables[0].Label, ShouldEqual, "UserName")
+		So(variables[0].Name, ShouldEqual, "user_name")
+		So(variables[1].Label, ShouldEqual, "Count")
+		So(variables[1].Pos, ShouldEqual, 2)
+		So(variables[1].Width, ShouldEqual, 5)
+		So(segments[4].Variable.Verb, ShouldEqual, Verb("q"))
+		So(segments[4].Variable.Pos, ShouldEqual, 1)
+
+		segments, variables, err = PythonDialect.Parse("%s: %#o %a %ld %%", ".Name")
+		So(err, ShouldEqual, nil)
+		So(len(variables), ShouldEqual, 4)
+		So(variables[0].Label, ShouldEqual, "Name")
+		So(variables[1].Verb, ShouldEqual, Verb("O"))
+		So(variables[1].Has(ModHash), ShouldBeFalse)
+		So(variables[2].Verb, ShouldEqual, Verb("q"))
+		So(variables[2].Has(ModPlus), ShouldBeTrue)
+		So(segments[len(segments)-1].Text, ShouldEqual, " %")
+
+		_, _, err = PythonDialect.Parse("%(name)s %s")
+		So(errors.Is(err, ErrMixedPositions), ShouldBeTrue)
+
+		_, _, err = PythonDialect.Parse("%(name")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: %(name")
+
+		_, _, err = PythonDialect.Parse("%k")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: %k")
+	})
+
+	Convey("Format", t, func() {
+		converted, err := Convert("%s has %d items (100%%)", GoDialect, PythonDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%s has %d items (100%%)")
+
+		replaced, _, _, err := DecomposeWith(PythonDialect, "%s has %d items", ".UserName", ".Count")
+		So(err, ShouldEqual, nil)
+		So(replaced, ShouldEqual, "%(user_name)s has %(count)d items")
+
+		converted, err = Convert("%(user_name)s has %(count)+d items", PythonDialect, GoDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%s has %+d items")
+
+		converted, err = Convert("%(count)d items of %(user_name)s", PythonDialect, PythonDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%(count)d items of %(user_name)s")
+
+		converted, err = Convert("%[2]O %[1]+q %[1]v", GoDialect, PythonDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%(num)#o %(var)a %(var)s")
+
+		converted, err = Convert("%U", GoDialect, PythonDialect)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(converted, ShouldEqual, "")
+	})
+}
+
+func TestPythonBraceDialect(t *testing.T) {
+	Convey("Parse", t, func() {
+		segments, variables, err := PythonBraceDialect.Parse("{0:>10.2f} {name!r} {{literal}} {1:<5} {name}", ".Price", ".Other")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 7)
+		So(segments[3].Text, ShouldEqual, " {literal} ")
+		So(len(variables), ShouldEqual, 3)
+		So(variables[0].Label, ShouldEqual, "Price")
+		So(variables[0].Verb, ShouldEqual, Verb("f"))
+		So(variables[0].Width, ShouldEqual, 10)
+		So(variables[0].Precision, ShouldEqual, 2)
+		So(variables[0].Has(ModMinus), ShouldBeFalse)
+		So(variables[1].Label, ShouldEqual, "Other")
+		So(variables[1].Verb, ShouldEqual, Verb("v"))
+		So(variables[1].Has(ModMinus), ShouldBeTrue)
+		So(variables[2].Label, ShouldEqual, "Name")
+		So(variables[2].Name, ShouldEqual, "name")
+		So(variables[2].Pos, ShouldEqual, 3)
+		So(variables[2].Verb, ShouldEqual, Verb("q"))
+
+		segments, variables, err = PythonBraceDialect.Parse("{} {:+#08x} {:10s} {!a}")
+		So(err, ShouldEqual, nil)
+		So(len(variables), ShouldEqual, 4)
+		So(variables[1].Has(ModPlus|ModHash|ModZeroPad), ShouldBeTrue)
+		So(variables[1].Width, ShouldEqual, 8)
+		So(variables[2].Has(ModMinus), ShouldBeTrue)
+		So(variables[3].Has(ModPlus), ShouldBeTrue)
+
+		_, _, err = PythonBraceDialect.Parse("{} {0}")
+		So(errors.Is(err, ErrMixedPositions), ShouldBeTrue)
+
+		_, _, err = PythonBraceDialect.Parse("{:*^10}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = PythonBraceDialect.Parse("{:^10}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = PythonBraceDialect.Parse("{:,d}")
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+
+		_, _, err = PythonBraceDialect.Parse("{:{width}}")
+		So(err, ShouldEqual, ErrPosArgNotImpl)
+
+		_, _, err = PythonBraceDialect.Parse("{!x}")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: {!x}")
+
+		_, _, err = PythonBraceDialect.Parse("{!r:d}")
+		So(err, ShouldNotEqual, nil)
+
+		_, _, err = PythonBraceDialect.Parse("oops }")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: }")
+
+		_, _, err = PythonBraceDialect.Parse("oops {")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: {")
+	})
+
+	Convey("Format", t, func() {
+		converted, err := Convert("%s has %d {items}", GoDialect, PythonBraceDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{} has {:d} {{items}}")
+
+		converted, err = Convert("%[2]d items belong to %-10[1]s", GoDialect, PythonBraceDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{1:d} items belong to {0:<10s}")
+
+		converted, err = Convert("%10s %+.2f %v %q", GoDialect, PythonBraceDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{:>10s} {:+.2f} {} {!r}")
+
+		converted, err = Convert("{0:>10.2f} {name!r}", PythonBraceDialect, GoDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%10.2f %q")
+
+		converted, err = Convert("{1} {0} {name}", PythonBraceDialect, PythonBraceDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "{1} {0} {name}")
+
+		converted, err = Convert("{user}: %(count)d", PythonBraceDialect, PythonDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%(user)s: %%(count)d")
+
+		converted, err = Convert("%T", GoDialect, PythonBraceDialect)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(converted, ShouldEqual, "")
+	})
+
+	Convey("Round trip", t, func() {
+		argv := []string{".Count", ".Name"}
+		converted, err := Convert("%[2]s owes %[1]d", GoDialect, PythonDialect, argv...)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%(name)s owes %(count)d")
+		converted, err = Convert(converted, PythonDialect, GoDialect, argv...)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%[2]s owes %[1]d")
+
+		// without argv, names are positioned by their first appearance
+		converted, err = Convert("%(name)s owes %(count)d", PythonDialect, GoDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%s owes %d")
+
+		// unmatched names take the free positions
+		_, variables, err := PythonDialect.Parse("%(other)s %(name)s %(total)d", argv...)
+		So(err, ShouldEqual, nil)
+		So(variables[0].Name, ShouldEqual, "other")
+		So(variables[0].Pos, ShouldEqual, 1)
+		So(variables[1].Name, ShouldEqual, "name")
+		So(variables[1].Pos, ShouldEqual, 2)
+		So(variables[2].Name, ShouldEqual, "total")
+		So(variables[2].Pos, ShouldEqual, 3)
+
+		segments, _, err := PythonBraceDialect.Parse("{name} owes {count:d}", argv...)
+		So(err, ShouldEqual, nil)
+		So(segments.String(), ShouldEqual, "%[2]v owes %[1]d")
+		segments, _, err = PythonBraceDialect.Parse("{0} {name} owes {count:d}", ".Extra", ".Count", ".Name")
+		So(err, ShouldEqual, nil)
+		So(segments.String(), ShouldEqual, "%v %[3]v owes %[2]d")
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoEquivalent = errors.New("no equivalent directive")
+)
+
+// Dialect is the interface for parsing and formatting the format strings of
+// a specific programming language, for example: Go's fmt package or C's
+// printf. All Dialects produce the same Segments and Variables model and so
+// format strings can be converted from one Dialect to another
+type Dialect interface {
+	// Name returns the short name of this Dialect, ie: "go" or "c"
+	Name() string
+	// Parse returns the literal text and substitution Variable Segments of
+	// the given format string, along with the sorted list of unique
+	// Variables, see Parse for more details
+	Parse(format string, argv ...string) (segments Segments, variables Variables, err error)
+	// Format returns the format string for the given Segments in this
+	// Dialect. When explicit is true, all substitution Variables include
+	// their argument positions, otherwise argument positions are only
+	// included where necessary. Format returns an ErrNoEquivalent error if
+	// any of the Variables cannot be represented in this Dialect
+	Format(segments Segments, explicit bool) (format string, err error)
+}
+
+var (
+	// GoDialect is the Dialect of Go's fmt package
+	GoDialect Dialect = cGoDialect{}
+)
+
+// Dialects returns the list of all Dialects supported by fmtstr
+func Dialects() (dialects []Dialect) {
+	dialects = []Dialect{GoDialect, CDialect, PythonDialect, PythonBraceDialect, ICUDialect}
+	return
+}
+
+// LookupDialect returns the Dialect with the given Name, or nil if not found
+func LookupDialect(name string) (dialect Dialect) {
+	for _, d := range Dialects() {
+		if d.Name() == name {
+			return d
+		}
+	}
+	return
+}
+
+// DecomposeWith is the Dialect equivalent of Decompose. The `replaced`
+// format string returned uses the given Dialect with explicit argument
+// positions for all substitution Variables
+func DecomposeWith(dialect Dialect, format string, argv ...string) (replaced, labelled string, variables Variables, err error) {
+	var segments Segments
+	if segments, variables, err = dialect.Parse(format, argv...); err != nil {
+		return
+	} else if replaced, err = dialect.Format(segments, true); err != nil {
+		variables = nil
+		return
+	}
+	labelled = segments.Labelled()
+	return
+}
+
+// Convert parses the format string with the `from` Dialect and returns the
+// equivalent format string in the `to` Dialect, using explicit argument
+// positions only where necessary. The optional `argv` list is used to label
+// the Variables, see Decompose, which is relevant when converting to
+// Dialects with named arguments
+func Convert(format string, from, to Dialect, argv ...string) (converted string, err error) {
+	var segments Segments
+	if segments, _, err = from.Parse(format, argv...); err != nil {
+		return
+	}
+	converted, err = to.Format(segments, false)
+	return
+}
+
+type cGoDialect struct{}
+
+func (d cGoDialect) Name() string {
+	return "go"
+}
+
+func (d cGoDialect) Parse(format string, argv ...string) (segments Segments, variables Variables, err error) {
+	segments, variables, err = Parse(format, argv...)
+	return
+}
+
+func (d cGoDialect) Format(segments Segments, explicit bool) (format string, err error) {
+	for _, variable := range segments.Variables() {
+		if !variable.Verb.IsGo() {
+			err = fmt.Errorf("%w: %q is not a %s verb", ErrNoEquivalent, variable.Source, d.Name())
+			return
+		}
+	}
+	if explicit {
+		format = segments.Replaced()
+		return
+	}
+	format = segments.String()
+	return
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDialect(t *testing.T) {
+	Convey("LookupDialect", t, func() {
+		So(LookupDialect("go"), ShouldEqual, GoDialect)
+		So(LookupDialect("c"), ShouldEqual, CDialect)
+		So(LookupDialect("nope"), ShouldEqual, nil)
+	})
+
+	Convey("DecomposeWith", t, func() {
+		replaced, labelled, variables, err := DecomposeWith(GoDialect, "%s has %d items", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(replaced, ShouldEqual, "%[1]s has %[2]d items")
+		So(labelled, ShouldEqual, "{Name} has {Count} items")
+		So(len(variables), ShouldEqual, 2)
+
+		replaced, labelled, variables, err = DecomposeWith(CDialect, "%s has %lld items (100%%)", "name", "count")
+		So(err, ShouldEqual, nil)
+		So(replaced, ShouldEqual, "%1$s has %2$lld items (100%%)")
+		So(labelled, ShouldEqual, "{Name} has {Count} items (100%%)")
+		So(len(variables), ShouldEqual, 2)
+
+		replaced, labelled, variables, err = DecomposeWith(CDialect, "%s %n")
+		So(err, ShouldEqual, nil)
+		So(replaced, ShouldEqual, "%1$s %2$n")
+		So(len(variables), ShouldEqual, 2)
+
+		replaced, labelled, variables, err = DecomposeWith(GoDialect, "%!")
+		So(err, ShouldNotEqual, nil)
+		So(replaced, ShouldEqual, "")
+		So(labelled, ShouldEqual, "")
+		So(len(variables), ShouldEqual, 0)
+	})
+
+	Convey("Convert", t, func() {
+		converted, err := Convert("%s has %d items", GoDialect, CDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%s has %d items")
+
+		converted, err = Convert("%[2]d items belong to %[1]s", GoDialect, CDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%2$d items belong to %1$s")
+
+		converted, err = Convert("%2$lld items belong to %1$-10s", CDialect, GoDialect)
+		So(err, ShouldEqual, nil)
+		So(converted, ShouldEqual, "%[2]d items belong to %-10[1]s")
+
+		converted, err = Convert("%v is %t", GoDialect, CDialect)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(converted, ShouldEqual, "")
+
+		converted, err = Convert("%d%n", CDialect, GoDialect)
+		So(errors.Is(err, ErrNoEquivalent), ShouldBeTrue)
+		So(converted, ShouldEqual, "")
+	})
+}
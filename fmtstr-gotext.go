@@ -0,0 +1,238 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// GotextFile is the structure of the golang.org/x/text/cmd/gotext JSON
+// files, ie: out.gotext.json and messages.gotext.json
+type GotextFile struct {
+	Language string           `json:"language"`
+	Messages []*GotextMessage `json:"messages"`
+}
+
+// GotextMessage is a single gotext JSON message, the Message and
+// Translation are in the gotext placeholder form, where each placeholder is
+// replaced with its curly-braced ID
+type GotextMessage struct {
+	ID                GotextID             `json:"id"`
+	Key               string               `json:"key,omitempty"`
+	Meaning           string               `json:"meaning,omitempty"`
+	Message           string               `json:"message"`
+	Translation       string               `json:"translation"`
+	Comment           string               `json:"comment,omitempty"`
+	TranslatorComment string               `json:"translatorComment,omitempty"`
+	Placeholders      []*GotextPlaceholder `json:"placeholders,omitempty"`
+	Fuzzy             bool                 `json:"fuzzy,omitempty"`
+	Position          string               `json:"position,omitempty"`
+}
+
+// GotextPlaceholder is a single gotext JSON message placeholder
+type GotextPlaceholder struct {
+	ID             string `json:"id"`
+	String         string `json:"string"`
+	Type           string `json:"type"`
+	UnderlyingType string `json:"underlyingType"`
+	ArgNum         int    `json:"argNum"`
+	Expr           string `json:"expr"`
+	Comment        string `json:"comment,omitempty"`
+	Example        string `json:"example,omitempty"`
+}
+
+// GotextID is the list of message IDs, which is a single string in the
+// gotext JSON files when there is only one ID
+type GotextID []string
+
+func (id GotextID) MarshalJSON() (data []byte, err error) {
+	if len(id) == 1 {
+		data, err = json.Marshal(id[0])
+		return
+	}
+	data, err = json.Marshal([]string(id))
+	return
+}
+
+func (id *GotextID) UnmarshalJSON(data []byte) (err error) {
+	var single string
+	if err = json.Unmarshal(data, &single); err == nil {
+		*id = GotextID{single}
+		return
+	}
+	var list []string
+	if err = json.Unmarshal(data, &list); err == nil {
+		*id = list
+	}
+	return
+}
+
+// NewGotextFile constructs a new GotextFile for the given language with a
+// GotextMessage for each of the source Messages given
+func NewGotextFile(language string, messages ...*Message) (file *GotextFile) {
+	file = &GotextFile{Language: language}
+	for _, message := range messages {
+		file.Messages = append(file.Messages, NewGotextMessage(message))
+	}
+	return
+}
+
+// ReadGotextFile parses the gotext JSON data from the given reader
+func ReadGotextFile(r io.Reader) (file *GotextFile, err error) {
+	file = &GotextFile{}
+	if err = json.NewDecoder(r).Decode(file); err != nil {
+		file = nil
+	}
+	return
+}
+
+// Write writes the gotext JSON data to the given writer, using the same
+// indentation as the gotext command
+func (f *GotextFile) Write(w io.Writer) (err error) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	err = encoder.Encode(f)
+	return
+}
+
+// NewGotextMessage returns the gotext JSON message for the given source
+// Message. Each of the Variables are mapped to a placeholder: the Label is
+// the ID, the Pos is the ArgNum, the Argv entry is the Expr and the Variable
+// String is the placeholder String. The placeholder Type and UnderlyingType
+// are derived from the Verb, see Verb.GoType
+func NewGotextMessage(source *Message) (message *GotextMessage) {
+	message = &GotextMessage{
+		ID:      GotextID{source.Labelled},
+		Key:     source.Format,
+		Message: source.Labelled,
+	}
+	for _, variable := range source.Variables {
+		var expr string
+		if variable.Pos <= len(source.Argv) {
+			expr = source.Argv[variable.Pos-1]
+		}
+		message.Placeholders = append(message.Placeholders, &GotextPlaceholder{
+			ID:             variable.Label,
+			String:         variable.String(),
+			Type:           variable.Verb.GoType(),
+			UnderlyingType: variable.Verb.GoType(),
+			ArgNum:         variable.Pos,
+			Expr:           expr,
+		})
+	}
+	return
+}
+
+// Source returns the source Message for this gotext message. The Key is used
+// as the source format string when present, otherwise the format string is
+// made by replacing the placeholder IDs in the Message with their Strings.
+// The Argv list is made from the placeholder Expr values
+func (m *GotextMessage) Source() (source *Message, err error) {
+	format := m.Key
+	if format == "" {
+		format = m.replace(m.Message)
+	}
+
+	var argv []string
+	for _, placeholder := range m.Placeholders {
+		if placeholder.ArgNum < 1 {
+			continue
+		}
+		for len(argv) < placeholder.ArgNum {
+			argv = append(argv, "")
+		}
+		argv[placeholder.ArgNum-1] = placeholder.Expr
+	}
+
+	source, err = NewMessage(format, argv...)
+	return
+}
+
+// Format returns the fmt format string of the Translation, made by replacing
+// the placeholder IDs with their Strings, and checks that it is a valid
+// translation of the Source message (see Message.Check)
+func (m *GotextMessage) Format() (translation string, err error) {
+	var source *Message
+	if source, err = m.Source(); err != nil {
+		return
+	}
+	translation = m.replace(m.Translation)
+	if _, err = source.Check(translation); err != nil {
+		translation = ""
+	}
+	return
+}
+
+// SetFormat checks that the given fmt format string is a valid translation
+// of the Source message (see Message.Check) and updates the Translation with
+// the placeholder form of the format string. Directives which differ from
+// the String of the placeholder for their argument, such as a translation
+// adding a width, are kept as literal directives with explicit indexes
+func (m *GotextMessage) SetFormat(translation string) (err error) {
+	var source *Message
+	if source, err = m.Source(); err != nil {
+		return
+	} else if _, err = source.Check(translation); err != nil {
+		return
+	}
+
+	var segments Segments
+	if segments, _, err = Parse(translation, source.Argv...); err != nil {
+		return
+	}
+	var text string
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			text += escapeText(segment.Text)
+			continue
+		}
+		directive := segment.Variable.String()
+		for _, placeholder := range m.Placeholders {
+			if placeholder.matches(segment.Variable) {
+				directive = "{" + placeholder.ID + "}"
+				break
+			}
+		}
+		text += directive
+	}
+	m.Translation = text
+	return
+}
+
+// matches returns true if the placeholder is for the argument of the given
+// Variable and its String is the same directive
+func (p *GotextPlaceholder) matches(variable *Variable) (match bool) {
+	if p.ArgNum != variable.Pos {
+		return
+	}
+	if _, variables, err := Parse(p.String); err == nil && len(variables) == 1 {
+		match = variables[0].directive(false) == variable.directive(false)
+	}
+	return
+}
+
+// replace substitutes the curly-braced placeholder IDs in the text given
+// with their placeholder Strings
+func (m *GotextMessage) replace(text string) (format string) {
+	var pairs []string
+	for _, placeholder := range m.Placeholders {
+		pairs = append(pairs, "{"+placeholder.ID+"}", placeholder.String)
+	}
+	format = strings.NewReplacer(pairs...).Replace(text)
+	return
+}
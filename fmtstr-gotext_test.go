@@ -0,0 +1,139 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testGotextJSON = `{
+    "language": "de",
+    "messages": [
+        {
+            "id": "{Name} has {Count} items",
+            "key": "%s has %d items",
+            "message": "{Name} has {Count} items",
+            "translation": "{Count} Artikel gehören {Name}",
+            "placeholders": [
+                {
+                    "id": "Name",
+                    "string": "%[1]s",
+                    "type": "string",
+                    "underlyingType": "string",
+                    "argNum": 1,
+                    "expr": ".Name"
+                },
+                {
+                    "id": "Count",
+                    "string": "%[2]d",
+                    "type": "int",
+                    "underlyingType": "int",
+                    "argNum": 2,
+                    "expr": ".Count"
+                }
+            ]
+        },
+        {
+            "id": ["Hello {City}!", "greeting"],
+            "message": "Hello {City}!",
+            "translation": "Hallo!",
+            "placeholders": [
+                {
+                    "id": "City",
+                    "string": "%[1]s",
+                    "type": "string",
+                    "underlyingType": "string",
+                    "argNum": 1,
+                    "expr": "city"
+                }
+            ],
+            "fuzzy": true
+        }
+    ]
+}
+`
+
+func TestGotext(t *testing.T) {
+	Convey("ReadGotextFile", t, func() {
+		file, err := ReadGotextFile(strings.NewReader(testGotextJSON))
+		So(err, ShouldEqual, nil)
+		So(file.Language, ShouldEqual, "de")
+		So(len(file.Messages), ShouldEqual, 2)
+		So(file.Messages[1].ID, ShouldResemble, GotextID{"Hello {City}!", "greeting"})
+		So(file.Messages[1].Fuzzy, ShouldBeTrue)
+
+		source, err := file.Messages[0].Source()
+		So(err, ShouldEqual, nil)
+		So(source.Format, ShouldEqual, "%s has %d items")
+		So(source.Argv, ShouldResemble, []string{".Name", ".Count"})
+
+		translation, err := file.Messages[0].Format()
+		So(err, ShouldEqual, nil)
+		So(translation, ShouldEqual, "%[2]d Artikel gehören %[1]s")
+
+		source, err = file.Messages[1].Source()
+		So(err, ShouldEqual, nil)
+		So(source.Format, ShouldEqual, "Hello %[1]s!")
+		So(source.Labelled, ShouldEqual, "Hello {City}!")
+
+		translation, err = file.Messages[1].Format()
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+		So(translation, ShouldEqual, "")
+
+		file, err = ReadGotextFile(strings.NewReader("{"))
+		So(err, ShouldNotEqual, nil)
+		So(file, ShouldBeNil)
+	})
+
+	Convey("Write", t, func() {
+		source, err := NewMessage("%s has %d items", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		file := NewGotextFile("de", source)
+		So(len(file.Messages), ShouldEqual, 1)
+		So(file.Messages[0].SetFormat("%[2]d Artikel gehören %[1]s"), ShouldEqual, nil)
+		So(file.Messages[0].Translation, ShouldEqual, "{Count} Artikel gehören {Name}")
+		So(errors.Is(file.Messages[0].SetFormat("%[2]d Artikel"), ErrInvalidTranslation), ShouldBeTrue)
+
+		// directives which differ from their placeholder are kept literally
+		So(file.Messages[0].SetFormat("%[2]05d Artikel gehören %[1]q"), ShouldEqual, nil)
+		So(file.Messages[0].Translation, ShouldEqual, "%05[2]d Artikel gehören %[1]q")
+		translation, err := file.Messages[0].Format()
+		So(err, ShouldEqual, nil)
+		So(translation, ShouldEqual, "%05[2]d Artikel gehören %[1]q")
+
+		// as are directives without a placeholder for their argument
+		placeholders := file.Messages[0].Placeholders
+		file.Messages[0].Placeholders = placeholders[:1]
+		So(file.Messages[0].SetFormat("%[2]d Artikel gehören %[1]s"), ShouldEqual, nil)
+		So(file.Messages[0].Translation, ShouldEqual, "%[2]d Artikel gehören {Name}")
+		file.Messages[0].Placeholders = placeholders
+		So(file.Messages[0].SetFormat("%[2]d Artikel gehören %[1]s"), ShouldEqual, nil)
+
+		var buf bytes.Buffer
+		So(file.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, strings.Split(testGotextJSON, `        },
+        {
+            "id": [`)[0]+"        }\n    ]\n}\n")
+
+		read, err := ReadGotextFile(&buf)
+		So(err, ShouldEqual, nil)
+		So(read, ShouldResemble, file)
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidVerb     = errors.New("invalid verb")
+	ErrInvalidModifier = errors.New("invalid modifier")
+	ErrInvalidVariable = errors.New("invalid variable")
+)
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (v Verb) MarshalText() (text []byte, err error) {
+	text = []byte(v)
+	return
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, the text
+// must not be empty
+func (v *Verb) UnmarshalText(text []byte) (err error) {
+	if len(text) == 0 {
+		err = fmt.Errorf("%w: empty", ErrInvalidVerb)
+		return
+	}
+	*v = Verb(text)
+	return
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, producing the
+// pipe-separated list of flag names, ie: `plus|zero`, or an empty string
+// when there are no modifiers
+func (m Modifier) MarshalText() (text []byte, err error) {
+	known := NoModifiers
+	for _, flag := range gModifierFlags {
+		known = known.With(flag.mod)
+	}
+	if unknown := m.Without(known); unknown != NoModifiers {
+		err = fmt.Errorf("%w: unknown bits %#x", ErrInvalidModifier, uint8(unknown))
+		return
+	}
+	text = []byte(strings.Join(m.names(), "|"))
+	return
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting
+// any of the forms supported by ParseModifier
+func (m *Modifier) UnmarshalText(text []byte) (err error) {
+	var parsed Modifier
+	if parsed, err = ParseModifier(string(text)); err == nil {
+		*m = parsed
+	}
+	return
+}
+
+// cVariable is the marshalled form of a Variable, with stable field names
+type cVariable struct {
+	Pos        int      `json:"pos" yaml:"pos"`
+	Verb       Verb     `json:"verb" yaml:"verb"`
+	Type       string   `json:"type" yaml:"type"`
+	Label      string   `json:"label" yaml:"label"`
+	Source     string   `json:"source" yaml:"source"`
+	Width      int      `json:"width,omitempty" yaml:"width,omitempty"`
+	Precision  int      `json:"precision,omitempty" yaml:"precision,omitempty"`
+	Modifiers  Modifier `json:"modifiers,omitempty" yaml:"modifiers,omitempty"`
+	Length     string   `json:"length,omitempty" yaml:"length,omitempty"`
+	Conversion string   `json:"conversion,omitempty" yaml:"conversion,omitempty"`
+	Name       string   `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+func (c *cVariable) variable() (variable *Variable, err error) {
+	variable = &Variable{
+		Type:       c.Type,
+		Label:      c.Label,
+		Source:     c.Source,
+		Pos:        c.Pos,
+		Verb:       c.Verb,
+		Width:      c.Width,
+		Precision:  c.Precision,
+		Modifiers:  c.Modifiers,
+		Length:     c.Length,
+		Conversion: c.Conversion,
+		Name:       c.Name,
+	}
+	if err = variable.Validate(); err != nil {
+		variable = nil
+	}
+	return
+}
+
+func (v *Variable) marshalled() (c *cVariable) {
+	c = &cVariable{
+		Pos:        v.Pos,
+		Verb:       v.Verb,
+		Type:       v.Type,
+		Label:      v.Label,
+		Source:     v.Source,
+		Width:      v.Width,
+		Precision:  v.Precision,
+		Modifiers:  v.Modifiers,
+		Length:     v.Length,
+		Conversion: v.Conversion,
+		Name:       v.Name,
+	}
+	return
+}
+
+// MarshalJSON implements the json.Marshaler interface, using lower-case
+// field names and the text forms of the Verb and Modifiers
+func (v *Variable) MarshalJSON() (data []byte, err error) {
+	data, err = json.Marshal(v.marshalled())
+	return
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, restoring the
+// Variable produced by MarshalJSON
+func (v *Variable) UnmarshalJSON(data []byte) (err error) {
+	var c cVariable
+	if err = json.Unmarshal(data, &c); err != nil {
+		return
+	}
+	var variable *Variable
+	if variable, err = c.variable(); err == nil {
+		*v = *variable
+	}
+	return
+}
+
+// MarshalYAML implements the yaml Marshaler interface, using the same field
+// names as MarshalJSON
+func (v *Variable) MarshalYAML() (value interface{}, err error) {
+	value = v.marshalled()
+	return
+}
+
+// UnmarshalYAML implements the yaml Unmarshaler interface, restoring the
+// Variable produced by MarshalYAML
+func (v *Variable) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var c cVariable
+	if err = unmarshal(&c); err != nil {
+		return
+	}
+	var variable *Variable
+	if variable, err = c.variable(); err == nil {
+		*v = *variable
+	}
+	return
+}
+
+// ParseVariables restores the Variables from the JSON data produced by
+// marshalling Variables
+func ParseVariables(data []byte) (variables Variables, err error) {
+	if err = json.Unmarshal(data, &variables); err != nil {
+		variables = nil
+	}
+	return
+}
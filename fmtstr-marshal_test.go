@@ -0,0 +1,106 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMarshal(t *testing.T) {
+	Convey("Verb", t, func() {
+		text, err := Verb("d").MarshalText()
+		So(err, ShouldEqual, nil)
+		So(string(text), ShouldEqual, "d")
+		var verb Verb
+		So(verb.UnmarshalText([]byte("x")), ShouldEqual, nil)
+		So(verb, ShouldEqual, Verb("x"))
+		So(errors.Is(verb.UnmarshalText(nil), ErrInvalidVerb), ShouldBeTrue)
+	})
+
+	Convey("Modifier", t, func() {
+		for _, test := range []struct {
+			mod  Modifier
+			text string
+		}{
+			{NoModifiers, ""},
+			{ModPlus, "plus"},
+			{ModPlus | ModZeroPad, "plus|zero"},
+			{ModMinus | ModHash | ModSpace | ModDecimal, "minus|hash|space|decimal"},
+		} {
+			text, err := test.mod.MarshalText()
+			So(err, ShouldEqual, nil)
+			So(string(text), ShouldEqual, test.text)
+			var mod Modifier
+			So(mod.UnmarshalText(text), ShouldEqual, nil)
+			So(mod, ShouldEqual, test.mod)
+		}
+
+		var mod Modifier
+		So(mod.UnmarshalText([]byte(" zero | plus ")), ShouldEqual, nil)
+		So(mod, ShouldEqual, ModPlus|ModZeroPad)
+		So(errors.Is(mod.UnmarshalText([]byte("plus|nope")), ErrInvalidModifier), ShouldBeTrue)
+		So(mod, ShouldEqual, ModPlus|ModZeroPad)
+		_, err := Modifier(0x80).MarshalText()
+		So(errors.Is(err, ErrInvalidModifier), ShouldBeTrue)
+	})
+
+	Convey("Variable JSON", t, func() {
+		v := &Variable{Type: "float", Label: "Total", Source: "%+08.2f", Pos: 2, Verb: "f", Width: 8, Precision: 2, Modifiers: ModPlus | ModZeroPad | ModDecimal}
+		data, err := json.Marshal(v)
+		So(err, ShouldEqual, nil)
+		So(string(data), ShouldEqual, `{"pos":2,"verb":"f","type":"float","label":"Total","source":"%+08.2f","width":8,"precision":2,"modifiers":"plus|decimal|zero"}`)
+
+		var restored Variable
+		So(json.Unmarshal(data, &restored), ShouldEqual, nil)
+		So(&restored, ShouldResemble, v)
+
+		So(errors.Is(json.Unmarshal([]byte(`{"pos":0,"verb":"d"}`), &restored), ErrInvalidVariable), ShouldBeTrue)
+		So(errors.Is(json.Unmarshal([]byte(`{"pos":1}`), &restored), ErrInvalidVariable), ShouldBeTrue)
+		So(errors.Is(json.Unmarshal([]byte(`{"pos":1,"verb":"d","modifiers":"bold"}`), &restored), ErrInvalidModifier), ShouldBeTrue)
+	})
+
+	Convey("Variable YAML", t, func() {
+		v := &Variable{Type: "text", Label: "Name", Source: "%(name)-10s", Pos: 1, Verb: "s", Width: 10, Modifiers: ModMinus, Name: "name"}
+		value, err := v.MarshalYAML()
+		So(err, ShouldEqual, nil)
+		data, err := json.Marshal(value)
+		So(err, ShouldEqual, nil)
+		So(string(data), ShouldEqual, `{"pos":1,"verb":"s","type":"text","label":"Name","source":"%(name)-10s","width":10,"modifiers":"minus","name":"name"}`)
+
+		var restored Variable
+		So(restored.UnmarshalYAML(func(target interface{}) error {
+			return json.Unmarshal(data, target)
+		}), ShouldEqual, nil)
+		So(&restored, ShouldResemble, v)
+	})
+
+	Convey("ParseVariables", t, func() {
+		_, _, variables, err := Decompose("%-5s %#x %.3f %t", ".Name", ".Count", ".Total", ".Ok")
+		So(err, ShouldEqual, nil)
+		data, err := json.Marshal(variables)
+		So(err, ShouldEqual, nil)
+		restored, err := ParseVariables(data)
+		So(err, ShouldEqual, nil)
+		So(restored, ShouldResemble, variables)
+
+		restored, err = ParseVariables([]byte(`[{"pos":1}]`))
+		So(errors.Is(err, ErrInvalidVariable), ShouldBeTrue)
+		So(restored, ShouldBeNil)
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrInvalidMask = errors.New("invalid mask")
+)
+
+const (
+	// MaskTokenXML is the XLIFF-style inline element mask token template
+	MaskTokenXML = `<x id="{id}"/>`
+	// MaskTokenUnderscore is the plain text mask token template
+	MaskTokenUnderscore = "__PH{id}__"
+)
+
+// MaskedFormat is a format string with each of the directives replaced by
+// an opaque token, see MaskWith
+type MaskedFormat struct {
+	// Format is the original format string
+	Format string
+	// Token is the token template, where `{id}` is replaced with the number
+	// of the directive
+	Token string
+	// Text is the Format with each directive replaced by its token and with
+	// all `%%` escapes unescaped
+	Text string
+	// Variables are the directives of the Format, in order, where the first
+	// directive has the id of 1
+	Variables []*Variable
+
+	pattern *regexp.Regexp
+}
+
+// Mask is a convenience wrapper around MaskWith, using the MaskTokenXML
+// token template
+func Mask(format string) (mask *MaskedFormat, err error) {
+	mask, err = MaskWith(format, MaskTokenXML)
+	return
+}
+
+// MaskWith replaces each of the directives in the given format string with
+// a machine translation safe token, made by replacing the `{id}` in the
+// token template with the number of the directive, counting from one. For
+// example: `%s has %d items` with the MaskTokenUnderscore template is masked
+// as `__PH1__ has __PH2__ items`.
+//
+// MaskWith returns an ErrInvalidMask error if the token template does not
+// contain `{id}` or if the literal text of the format string already contains
+// something that looks like a token
+func MaskWith(format, token string) (mask *MaskedFormat, err error) {
+	if !strings.Contains(token, "{id}") {
+		err = fmt.Errorf("%w: token %q is missing {id}", ErrInvalidMask, token)
+		return
+	}
+
+	var segments Segments
+	if segments, _, err = Parse(format); err != nil {
+		return
+	}
+
+	mask = &MaskedFormat{
+		Format:  format,
+		Token:   token,
+		pattern: maskPattern(token),
+	}
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			if found := mask.pattern.FindString(segment.Text); found != "" {
+				err = fmt.Errorf("%w: text contains token %q", ErrInvalidMask, found)
+				mask = nil
+				return
+			}
+			mask.Text += segment.Text
+			continue
+		}
+		mask.Variables = append(mask.Variables, segment.Variable)
+		mask.Text += strings.ReplaceAll(token, "{id}", strconv.Itoa(len(mask.Variables)))
+	}
+	return
+}
+
+// Unmask is the inverse of Mask, Unmask replaces each of the tokens in the
+// given text with the directive they masked, escaping any percent signs in
+// the text. Tokens may be reordered and are matched ignoring case and any
+// whitespace around the token punctuation (ie: `<x id = "1" />`), explicit
+// argument indexes are added only where the order differs from the implicit
+// argument order.
+//
+// Unmask returns an ErrInvalidMask error listing all of the tokens that were
+// dropped, duplicated or not present in the mask
+func Unmask(text string, mask *MaskedFormat) (format string, err error) {
+	pattern := mask.pattern
+	if pattern == nil {
+		pattern = maskPattern(mask.Token)
+	}
+
+	var segments Segments
+	counts := make(map[int]int)
+	var unknown []string
+
+	var last int
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		id, _ := strconv.Atoi(text[match[2]:match[3]])
+		if id < 1 || id > len(mask.Variables) {
+			unknown = append(unknown, text[match[0]:match[1]])
+			continue
+		}
+		counts[id] += 1
+		if match[0] > last {
+			segments = append(segments, &Segment{Text: text[last:match[0]]})
+		}
+		segments = append(segments, &Segment{Variable: mask.Variables[id-1]})
+		last = match[1]
+	}
+	if last < len(text) {
+		segments = append(segments, &Segment{Text: text[last:]})
+	}
+
+	var problems []string
+	var dropped, duplicated []int
+	for id := 1; id <= len(mask.Variables); id++ {
+		if count := counts[id]; count == 0 {
+			dropped = append(dropped, id)
+		} else if count > 1 {
+			duplicated = append(duplicated, id)
+		}
+	}
+	if len(dropped) > 0 {
+		problems = append(problems, fmt.Sprintf("dropped tokens: %v", dropped))
+	}
+	if len(duplicated) > 0 {
+		problems = append(problems, fmt.Sprintf("duplicated tokens: %v", duplicated))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown tokens: %q", unknown))
+	}
+	if len(problems) > 0 {
+		err = fmt.Errorf("%w: %s", ErrInvalidMask, strings.Join(problems, "; "))
+		return
+	}
+
+	format = segments.String()
+	return
+}
+
+// maskPattern returns the regular expression matching the token template
+// given, where the `{id}` is captured and whitespace is optional around
+// any of the punctuation
+func maskPattern(token string) (pattern *regexp.Regexp) {
+	var buf strings.Builder
+	buf.WriteString(`(?i)`)
+	var previous rune
+	for idx, part := range strings.Split(token, "{id}") {
+		if idx > 0 {
+			buf.WriteString(`\s*(\d+)`)
+			previous = '}'
+		}
+		var space bool
+		for _, r := range part {
+			if unicode.IsSpace(r) {
+				space = true
+				continue
+			}
+			if previous != 0 && (isMaskPunct(previous) || isMaskPunct(r)) {
+				buf.WriteString(`\s*`)
+			} else if space {
+				buf.WriteString(`\s+`)
+			}
+			buf.WriteString(regexp.QuoteMeta(string(r)))
+			previous, space = r, false
+		}
+	}
+	pattern = regexp.MustCompile(buf.String())
+	return
+}
+
+func isMaskPunct(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMask(t *testing.T) {
+	Convey("Mask", t, func() {
+		mask, err := Mask("%s has %[2]d items (100%%)")
+		So(err, ShouldEqual, nil)
+		So(mask.Text, ShouldEqual, `<x id="1"/> has <x id="2"/> items (100%)`)
+		So(len(mask.Variables), ShouldEqual, 2)
+
+		mask, err = MaskWith("%s has %d items", MaskTokenUnderscore)
+		So(err, ShouldEqual, nil)
+		So(mask.Text, ShouldEqual, "__PH1__ has __PH2__ items")
+
+		_, err = MaskWith("%s", "__PH__")
+		So(errors.Is(err, ErrInvalidMask), ShouldBeTrue)
+		_, err = MaskWith("__PH1__ %s", MaskTokenUnderscore)
+		So(err.Error(), ShouldEqual, `invalid mask: text contains token "__PH1__"`)
+		_, err = Mask("%[1]d %[1]s")
+		So(err, ShouldNotEqual, nil)
+	})
+
+	Convey("Unmask", t, func() {
+		mask, _ := Mask("%s has %d items (100%%)")
+
+		format, err := Unmask(`<x id="1"/> hat <x id="2"/> Artikel (100%)`, mask)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%s hat %d Artikel (100%%)")
+
+		format, err = Unmask(`<X ID = "2" /> Artikel gehören <x id="1"/>`, mask)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[2]d Artikel gehören %[1]s")
+
+		mask, _ = MaskWith("%s has %d items", MaskTokenUnderscore)
+		format, err = Unmask("__ph2__ items for __PH 1__", mask)
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%[2]d items for %[1]s")
+
+		_, err = Unmask("__PH1__ __PH1__ __PH3__", mask)
+		So(errors.Is(err, ErrInvalidMask), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, `invalid mask: dropped tokens: [2]; duplicated tokens: [1]; unknown tokens: ["__PH3__"]`)
+
+		format, err = Unmask("__PH1__ __PH2__", &MaskedFormat{Token: mask.Token, Variables: mask.Variables})
+		So(err, ShouldEqual, nil)
+		So(format, ShouldEqual, "%s %d")
+	})
+}
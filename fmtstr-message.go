@@ -0,0 +1,67 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidTranslation = errors.New("invalid translation")
+)
+
+// Message is a decomposed source format string, along with the argv list
+// used to label the Variables, see Decompose
+type Message struct {
+	Format    string
+	Argv      []string
+	Replaced  string
+	Labelled  string
+	Variables Variables
+}
+
+// NewMessage decomposes the given format string and argv list into a new
+// Message instance
+func NewMessage(format string, argv ...string) (message *Message, err error) {
+	message = &Message{
+		Format: format,
+		Argv:   argv,
+	}
+	if message.Replaced, message.Labelled, message.Variables, err = Decompose(format, argv...); err != nil {
+		message = nil
+	}
+	return
+}
+
+// Check decomposes the given translation of this Message, using the Message
+// Argv list, and returns an ErrInvalidTranslation error if the translation
+// is not a valid format string, uses any argument positions not present in
+// the Message, has conflicting substitution types or does not use all of
+// the Message Variables
+func (m *Message) Check(translation string) (variables Variables, err error) {
+	if _, _, variables, err = Decompose(translation, m.Argv...); err == nil {
+		if err = m.Variables.Compatible(variables); err == nil {
+			if missing := m.Variables.Missing(variables); len(missing) > 0 {
+				err = fmt.Errorf("missing argument: %v", missing[0])
+			}
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %q: %w", ErrInvalidTranslation, translation, err)
+		variables = nil
+	}
+	return
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessage(t *testing.T) {
+	Convey("NewMessage", t, func() {
+		message, err := NewMessage("%s has %d items", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+		So(message.Replaced, ShouldEqual, "%[1]s has %[2]d items")
+		So(message.Labelled, ShouldEqual, "{Name} has {Count} items")
+		So(len(message.Variables), ShouldEqual, 2)
+
+		message, err = NewMessage("%!")
+		So(err, ShouldNotEqual, nil)
+		So(message, ShouldBeNil)
+	})
+
+	Convey("Check", t, func() {
+		message, _ := NewMessage("%s has %d items", ".Name", ".Count")
+
+		variables, err := message.Check("%[2]d items belong to %[1]s")
+		So(err, ShouldEqual, nil)
+		So(len(variables), ShouldEqual, 2)
+
+		variables, err = message.Check("%[2]d items")
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, `invalid translation: "%[2]d items": missing argument: %[1]s`)
+		So(variables, ShouldBeNil)
+
+		_, err = message.Check("%s has %d items in %s")
+		So(err.Error(), ShouldEqual, `invalid translation: "%s has %d items in %s": unknown argument: %[3]s`)
+
+		_, err = message.Check("%d has %d items")
+		So(err.Error(), ShouldEqual, `invalid translation: "%d has %d items": conflicting substitution types: %[1]s != %[1]d`)
+
+		_, err = message.Check("%!")
+		So(err.Error(), ShouldEqual, `invalid translation: "%!": invalid format at: %!`)
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gModifierFlags are the flag characters and text names of the Modifier
+// flags, in bit order
+var gModifierFlags = []struct {
+	mod  Modifier
+	char byte
+	name string
+}{
+	{ModPlus, '+', "plus"},
+	{ModMinus, '-', "minus"},
+	{ModHash, '#', "hash"},
+	{ModSpace, ' ', "space"},
+	{ModDecimal, '.', "decimal"},
+	{ModZeroPad, '0', "zero"},
+}
+
+// gModifierChars are the Modifier flag characters in the order they are
+// written in format directives, see Variable.String
+const gModifierChars = "#+- 0."
+
+// ParseModifier parses the given Modifier text, which is either the flag
+// characters (ie: `+0`), the pipe-separated flag names (ie: `plus|zero`), or
+// both as produced by Modifier.String (ie: `+0 (plus|zero)`). An empty text
+// or `none` is NoModifiers
+func ParseModifier(text string) (m Modifier, err error) {
+	if text == "" || text == "none" {
+		return
+	}
+
+	if idx := strings.LastIndex(text, " ("); idx > -1 && strings.HasSuffix(text, ")") {
+		var chars, names Modifier
+		if chars, err = parseModifierChars(text[:idx]); err != nil {
+			return
+		} else if names, err = parseModifierNames(text[idx+2 : len(text)-1]); err != nil {
+			return
+		} else if chars != names {
+			err = fmt.Errorf("%w: flags and names differ: %q", ErrInvalidModifier, text)
+			return
+		}
+		m = chars
+		return
+	}
+
+	if strings.Trim(text, gModifierChars) == "" {
+		m, err = parseModifierChars(text)
+		return
+	}
+	m, err = parseModifierNames(text)
+	return
+}
+
+func parseModifierChars(text string) (m Modifier, err error) {
+	for idx := 0; idx < len(text); idx++ {
+		var found bool
+		for _, flag := range gModifierFlags {
+			if found = flag.char == text[idx]; found {
+				m |= flag.mod
+				break
+			}
+		}
+		if !found {
+			err = fmt.Errorf("%w: unknown flag %q", ErrInvalidModifier, text[idx])
+			return
+		}
+	}
+	return
+}
+
+func parseModifierNames(text string) (m Modifier, err error) {
+	for _, name := range strings.Split(text, "|") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		var found bool
+		for _, flag := range gModifierFlags {
+			if found = flag.name == name; found {
+				m |= flag.mod
+				break
+			}
+		}
+		if !found {
+			err = fmt.Errorf("%w: %q", ErrInvalidModifier, name)
+			return
+		}
+	}
+	return
+}
+
+// String returns the flag characters followed by the flag names, ie:
+// `+0 (plus|zero)`, or `none` when there are no modifiers. Any unknown bits
+// are included in hexadecimal
+func (m Modifier) String() (value string) {
+	if m == NoModifiers {
+		return "none"
+	}
+	value = m.chars() + " (" + strings.Join(m.names(), "|") + ")"
+	return
+}
+
+// chars returns the flag characters of the Modifier in directive order
+func (m Modifier) chars() (value string) {
+	for idx := 0; idx < len(gModifierChars); idx++ {
+		for _, flag := range gModifierFlags {
+			if flag.char == gModifierChars[idx] && m.Has(flag.mod) {
+				value += string(flag.char)
+			}
+		}
+	}
+	return
+}
+
+// names returns the flag names of the Modifier in bit order, including any
+// unknown bits in hexadecimal
+func (m Modifier) names() (names []string) {
+	for _, flag := range gModifierFlags {
+		if m.Has(flag.mod) {
+			names = append(names, flag.name)
+			m = m.Without(flag.mod)
+		}
+	}
+	if m != NoModifiers {
+		names = append(names, fmt.Sprintf("%#x", uint8(m)))
+	}
+	return
+}
+
+// Has returns true if all the given flags are present
+func (m Modifier) Has(flags Modifier) (present bool) {
+	present = m&flags == flags
+	return
+}
+
+// Any returns true if any of the given flags are present
+func (m Modifier) Any(flags Modifier) (present bool) {
+	present = m&flags != NoModifiers
+	return
+}
+
+// With returns the Modifier with all the given flags added
+func (m Modifier) With(flags ...Modifier) (modified Modifier) {
+	modified = m
+	for _, flag := range flags {
+		modified |= flag
+	}
+	return
+}
+
+// Without returns the Modifier with all the given flags removed
+func (m Modifier) Without(flags ...Modifier) (modified Modifier) {
+	modified = m
+	for _, flag := range flags {
+		modified &^= flag
+	}
+	return
+}
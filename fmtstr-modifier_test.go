@@ -0,0 +1,78 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestModifier(t *testing.T) {
+	Convey("String", t, func() {
+		So(NoModifiers.String(), ShouldEqual, "none")
+		So(ModPlus.String(), ShouldEqual, "+ (plus)")
+		So((ModPlus | ModZeroPad).String(), ShouldEqual, "+0 (plus|zero)")
+		So((ModHash | ModMinus | ModSpace | ModDecimal).String(), ShouldEqual, "#- . (minus|hash|space|decimal)")
+		So((ModPlus | Modifier(0x80)).String(), ShouldEqual, "+ (plus|0x80)")
+		So(fmt.Sprintf("%v", ModZeroPad), ShouldEqual, "0 (zero)")
+	})
+
+	Convey("ParseModifier", t, func() {
+		for _, test := range []struct {
+			text string
+			mod  Modifier
+		}{
+			{"", NoModifiers},
+			{"none", NoModifiers},
+			{"+0", ModPlus | ModZeroPad},
+			{" ", ModSpace},
+			{"plus|zero", ModPlus | ModZeroPad},
+			{" zero | plus ", ModPlus | ModZeroPad},
+			{"+0 (plus|zero)", ModPlus | ModZeroPad},
+			{"  (space)", ModSpace},
+			{"#- . (minus|hash|space|decimal)", ModHash | ModMinus | ModSpace | ModDecimal},
+		} {
+			mod, err := ParseModifier(test.text)
+			So(err, ShouldEqual, nil)
+			So(mod, ShouldEqual, test.mod)
+		}
+
+		for mod := NoModifiers; mod < ModZeroPad<<1; mod += ModPlus {
+			parsed, err := ParseModifier(mod.String())
+			So(err, ShouldEqual, nil)
+			So(parsed, ShouldEqual, mod)
+		}
+
+		for _, text := range []string{"bold", "plus|bold", "+x", "+ (zero)", "+* (plus)"} {
+			_, err := ParseModifier(text)
+			So(errors.Is(err, ErrInvalidModifier), ShouldBeTrue)
+		}
+	})
+
+	Convey("Set algebra", t, func() {
+		mod := NoModifiers.With(ModPlus, ModZeroPad)
+		So(mod, ShouldEqual, ModPlus|ModZeroPad)
+		So(mod.Has(ModPlus), ShouldBeTrue)
+		So(mod.Has(ModPlus|ModMinus), ShouldBeFalse)
+		So(mod.Any(ModPlus|ModMinus), ShouldBeTrue)
+		So(mod.Any(ModMinus|ModHash), ShouldBeFalse)
+		So(mod.Without(ModPlus), ShouldEqual, ModZeroPad)
+		So(mod.Without(ModPlus, ModZeroPad, ModHash), ShouldEqual, NoModifiers)
+		So(mod.With(), ShouldEqual, mod)
+	})
+}
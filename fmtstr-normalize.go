@@ -0,0 +1,73 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+// Normalize returns the canonical form of the given format string. The
+// canonical form uses explicit argument indexes only where the implicit
+// argument order would not produce the correct argument, writes the flags
+// of each substitution Variable in a consistent order (`#`, `+`, `-`, ` `
+// and `0`) without any duplicates, drops the `0` flag when combined with
+// the `-` flag (as fmt ignores zero padding on the right) and drops the ` `
+// flag when combined with the `+` flag (as fmt ignores the space sign).
+//
+// For example: `%[1]s %[2]d %0-+5d` and `%s %d %+-5[3]d` both normalize to
+// `%s %d %+-5d`
+func Normalize(format string) (normalized string, err error) {
+	normalized, err = canonicalize(format, false)
+	return
+}
+
+// Collapse is the same as Normalize except that verbs which fmt treats as
+// exact synonyms are also collapsed into their Verb.Canonical form, for
+// example: `%F` is collapsed to `%f`
+func Collapse(format string) (collapsed string, err error) {
+	collapsed, err = canonicalize(format, true)
+	return
+}
+
+// Equivalent returns true if both format strings are valid and Collapse to
+// the same canonical form, for example: `%[1]s: %[2]F` is Equivalent to
+// `%s: %f`
+func Equivalent(a, b string) (equivalent bool) {
+	var err error
+	var first, second string
+	if first, err = Collapse(a); err != nil {
+		return
+	} else if second, err = Collapse(b); err != nil {
+		return
+	}
+	equivalent = first == second
+	return
+}
+
+func canonicalize(format string, collapse bool) (canonical string, err error) {
+	var segments Segments
+	if segments, _, err = Parse(format); err != nil {
+		return
+	}
+	for _, variable := range segments.Variables() {
+		if variable.Has(ModMinus) {
+			variable.Modifiers &^= ModZeroPad
+		}
+		if variable.Has(ModPlus) {
+			variable.Modifiers &^= ModSpace
+		}
+		if collapse {
+			variable.Verb = variable.Verb.Canonical()
+		}
+	}
+	canonical = segments.String()
+	return
+}
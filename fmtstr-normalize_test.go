@@ -0,0 +1,80 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNormalize(t *testing.T) {
+	Convey("Normalize", t, func() {
+		normalized, err := Normalize("")
+		So(err, ShouldEqual, nil)
+		So(normalized, ShouldEqual, "")
+
+		normalized, err = Normalize("%[1]s %[2]d %0-+5d")
+		So(err, ShouldEqual, nil)
+		So(normalized, ShouldEqual, "%s %d %+-5d")
+
+		normalized, err = Normalize("%s %d %+-5[3]d")
+		So(err, ShouldEqual, nil)
+		So(normalized, ShouldEqual, "%s %d %+-5d")
+
+		normalized, err = Normalize("%[2]s %[1]s %% %[3]F")
+		So(err, ShouldEqual, nil)
+		So(normalized, ShouldEqual, "%[2]s %[1]s %% %[3]F")
+
+		normalized, err = Normalize("%+ d % +5d % d")
+		So(err, ShouldEqual, nil)
+		So(normalized, ShouldEqual, "%+d %+5d % d")
+
+		normalized, err = Normalize("%.0f %08.0f %5.00f %.10f")
+		So(err, ShouldEqual, nil)
+		So(normalized, ShouldEqual, "%.f %08.f %5.f %.10f")
+
+		_, _, variables, err := Decompose("%.0f %08.10f")
+		So(err, ShouldEqual, nil)
+		So(variables[0].Has(ModZeroPad), ShouldBeFalse)
+		So(variables[0].Has(ModDecimal), ShouldBeTrue)
+		So(variables[1].Has(ModZeroPad), ShouldBeTrue)
+		So(variables[1].Width, ShouldEqual, 8)
+		So(variables[1].Precision, ShouldEqual, 10)
+
+		normalized, err = Normalize("%[1]d %[1]s")
+		So(err, ShouldNotEqual, nil)
+		So(normalized, ShouldEqual, "")
+	})
+
+	Convey("Collapse", t, func() {
+		collapsed, err := Collapse("%[2]s %[1]s %% %[3]F")
+		So(err, ShouldEqual, nil)
+		So(collapsed, ShouldEqual, "%[2]s %[1]s %% %[3]f")
+
+		collapsed, err = Collapse("%!")
+		So(err, ShouldNotEqual, nil)
+		So(collapsed, ShouldEqual, "")
+	})
+
+	Convey("Equivalent", t, func() {
+		So(Equivalent("", ""), ShouldBeTrue)
+		So(Equivalent("%[1]s: %[2]F", "%s: %f"), ShouldBeTrue)
+		So(Equivalent("%++d", "%+d"), ShouldBeTrue)
+		So(Equivalent("%s: %d", "%s; %d"), ShouldBeFalse)
+		So(Equivalent("%[2]s %[1]s", "%s %s"), ShouldBeFalse)
+		So(Equivalent("%!", "%!"), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,376 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidPlural = errors.New("invalid plural")
+)
+
+// PluralCategory is one of the CLDR plural categories
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// PluralCategories is the list of all CLDR plural categories, in CLDR order
+var PluralCategories = []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+// PluralRule is the CLDR plural rule of a language, for integer operands
+type PluralRule struct {
+	// Categories is the list of categories used by this rule, in CLDR order
+	// and always including PluralOther
+	Categories []PluralCategory
+	// Select returns the category of the integer given
+	Select func(n int) PluralCategory
+}
+
+// Has returns true if the given category is used by this PluralRule
+func (r *PluralRule) Has(category PluralCategory) (present bool) {
+	for _, c := range r.Categories {
+		if present = c == category; present {
+			return
+		}
+	}
+	return
+}
+
+var (
+	gPluralOtherOnly = &PluralRule{
+		Categories: []PluralCategory{PluralOther},
+		Select: func(n int) PluralCategory {
+			return PluralOther
+		},
+	}
+	gPluralOneOther = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			}
+			return PluralOther
+		},
+	}
+	gPluralZeroOneOther = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 0 || n == 1 {
+				return PluralOne
+			}
+			return PluralOther
+		},
+	}
+	gPluralOneManyOther = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralMany, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			} else if n != 0 && n%1000000 == 0 {
+				return PluralMany
+			}
+			return PluralOther
+		},
+	}
+	gPluralZeroOneManyOther = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralMany, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 0 || n == 1 {
+				return PluralOne
+			} else if n%1000000 == 0 {
+				return PluralMany
+			}
+			return PluralOther
+		},
+	}
+	gPluralSlavic = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany, PluralOther},
+		Select: func(n int) PluralCategory {
+			n10, n100 := n%10, n%100
+			if n10 == 1 && n100 != 11 {
+				return PluralOne
+			} else if n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14) {
+				return PluralFew
+			}
+			return PluralMany
+		},
+	}
+	gPluralPolish = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany, PluralOther},
+		Select: func(n int) PluralCategory {
+			n10, n100 := n%10, n%100
+			if n == 1 {
+				return PluralOne
+			} else if n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14) {
+				return PluralFew
+			}
+			return PluralMany
+		},
+	}
+	gPluralCzech = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			} else if n >= 2 && n <= 4 {
+				return PluralFew
+			}
+			return PluralOther
+		},
+	}
+	gPluralArabic = &PluralRule{
+		Categories: PluralCategories,
+		Select: func(n int) PluralCategory {
+			n100 := n % 100
+			switch {
+			case n == 0:
+				return PluralZero
+			case n == 1:
+				return PluralOne
+			case n == 2:
+				return PluralTwo
+			case n100 >= 3 && n100 <= 10:
+				return PluralFew
+			case n100 >= 11 && n100 <= 99:
+				return PluralMany
+			}
+			return PluralOther
+		},
+	}
+	gPluralHebrew = &PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralTwo, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			} else if n == 2 {
+				return PluralTwo
+			}
+			return PluralOther
+		},
+	}
+	gPluralWelsh = &PluralRule{
+		Categories: PluralCategories,
+		Select: func(n int) PluralCategory {
+			switch n {
+			case 0:
+				return PluralZero
+			case 1:
+				return PluralOne
+			case 2:
+				return PluralTwo
+			case 3:
+				return PluralFew
+			case 6:
+				return PluralMany
+			}
+			return PluralOther
+		},
+	}
+)
+
+var gPluralRules = map[string]*PluralRule{
+	"ar":    gPluralArabic,
+	"be":    gPluralSlavic,
+	"bg":    gPluralOneOther,
+	"cs":    gPluralCzech,
+	"cy":    gPluralWelsh,
+	"da":    gPluralOneOther,
+	"de":    gPluralOneOther,
+	"el":    gPluralOneOther,
+	"en":    gPluralOneOther,
+	"es":    gPluralOneManyOther,
+	"et":    gPluralOneOther,
+	"fa":    gPluralZeroOneOther,
+	"fi":    gPluralOneOther,
+	"fr":    gPluralZeroOneManyOther,
+	"he":    gPluralHebrew,
+	"hi":    gPluralZeroOneOther,
+	"hu":    gPluralOneOther,
+	"id":    gPluralOtherOnly,
+	"it":    gPluralOneManyOther,
+	"ja":    gPluralOtherOnly,
+	"ko":    gPluralOtherOnly,
+	"ms":    gPluralOtherOnly,
+	"nb":    gPluralOneOther,
+	"nl":    gPluralOneOther,
+	"pl":    gPluralPolish,
+	"pt":    gPluralZeroOneManyOther,
+	"pt-pt": gPluralOneManyOther,
+	"ru":    gPluralSlavic,
+	"sk":    gPluralCzech,
+	"sv":    gPluralOneOther,
+	"th":    gPluralOtherOnly,
+	"tr":    gPluralOneOther,
+	"uk":    gPluralSlavic,
+	"vi":    gPluralOtherOnly,
+	"zh":    gPluralOtherOnly,
+}
+
+// LookupPluralRule returns the embedded PluralRule for the given locale (ie:
+// "pt-PT" or "pt_PT"), falling back to the rule for the language of the
+// locale (ie: "en" for "en-US"), or nil if not found
+func LookupPluralRule(locale string) (rule *PluralRule) {
+	language := strings.ReplaceAll(strings.ToLower(locale), "_", "-")
+	if rule = gPluralRules[language]; rule != nil {
+		return
+	}
+	if idx := strings.IndexByte(language, '-'); idx > -1 {
+		language = language[:idx]
+	}
+	rule = gPluralRules[language]
+	return
+}
+
+// Plural is a message with a separate fmt format string for each of the
+// CLDR plural categories, where the case is selected by the value of the
+// num-typed Variable at the argument position Pos
+type Plural struct {
+	// Pos is the argument position of the num-typed Variable which selects
+	// the plural case
+	Pos int
+	// Cases are the format strings for each plural category, the
+	// PluralOther case is required
+	Cases map[PluralCategory]string
+	// Argv is the optional list of argument names, see Decompose
+	Argv []string
+}
+
+// NewPlural constructs a new Plural instance and returns any Validate error
+func NewPlural(pos int, cases map[PluralCategory]string, argv ...string) (plural *Plural, err error) {
+	plural = &Plural{
+		Pos:   pos,
+		Cases: cases,
+		Argv:  argv,
+	}
+	if err = plural.Validate(); err != nil {
+		plural = nil
+	}
+	return
+}
+
+// Validate checks that the PluralOther case is present, that all cases are
+// valid format strings (using Decompose) and that all cases are compatible
+// with the PluralOther case. Compatible cases only use argument positions
+// present in the PluralOther case, with the same substitution types, and
+// the Variable at Pos must be num-typed
+func (p *Plural) Validate() (err error) {
+	for category := range p.Cases {
+		if !isPluralCategory(category) {
+			err = fmt.Errorf("%w: unknown category %q", ErrInvalidPlural, category)
+			return
+		}
+	}
+
+	var other Variables
+	if other, err = p.Variables(); err != nil {
+		return
+	}
+	if found := other.Lookup(p.Pos); found == nil {
+		err = fmt.Errorf("%w: %s case does not use argument %d", ErrInvalidPlural, PluralOther, p.Pos)
+		return
+	} else if found.Type != "num" {
+		err = fmt.Errorf("%w: argument %d is not a number: %v", ErrInvalidPlural, p.Pos, found)
+		return
+	}
+
+	for _, category := range PluralCategories {
+		if format, present := p.Cases[category]; present && category != PluralOther {
+			var variables Variables
+			if _, _, variables, err = Decompose(format, p.Argv...); err == nil {
+				err = other.Compatible(variables)
+			}
+			if err != nil {
+				err = fmt.Errorf("%w: %s case: %w", ErrInvalidPlural, category, err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// Variables returns the Decompose Variables of the PluralOther case
+func (p *Plural) Variables() (variables Variables, err error) {
+	if format, present := p.Cases[PluralOther]; !present {
+		err = fmt.Errorf("%w: missing %s case", ErrInvalidPlural, PluralOther)
+	} else if _, _, variables, err = Decompose(format, p.Argv...); err != nil {
+		err = fmt.Errorf("%w: %s case: %w", ErrInvalidPlural, PluralOther, err)
+	}
+	return
+}
+
+// Select returns the format string of the case selected by the PluralRule
+// for the given count, falling back to the PluralOther case. A nil rule
+// always selects the PluralOther case
+func (p *Plural) Select(rule *PluralRule, count int) (format string) {
+	if rule != nil {
+		if found, present := p.Cases[rule.Select(count)]; present {
+			return found
+		}
+	}
+	format = p.Cases[PluralOther]
+	return
+}
+
+// Sprintf selects the case using the integer argument at Pos and returns
+// the result of formatting the case with the arguments given. The case is
+// formatted with Segments.Sprintf so that cases which do not use all of the
+// arguments are formatted correctly
+func (p *Plural) Sprintf(rule *PluralRule, argv ...interface{}) (result string, err error) {
+	if p.Pos < 1 || p.Pos > len(argv) {
+		err = fmt.Errorf("%w: missing argument %d", ErrInvalidPlural, p.Pos)
+		return
+	}
+
+	var count int
+	switch value := reflect.ValueOf(argv[p.Pos-1]); value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		count = int(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		count = int(value.Uint())
+	default:
+		err = fmt.Errorf("%w: argument %d is not an integer: %T", ErrInvalidPlural, p.Pos, argv[p.Pos-1])
+		return
+	}
+	if count < 0 {
+		count = -count
+	}
+
+	var segments Segments
+	if segments, _, err = Parse(p.Select(rule, count), p.Argv...); err != nil {
+		return
+	}
+	result = segments.Sprintf(argv...)
+	return
+}
+
+func isPluralCategory(category PluralCategory) (valid bool) {
+	for _, c := range PluralCategories {
+		if valid = c == category; valid {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPlural(t *testing.T) {
+	Convey("LookupPluralRule", t, func() {
+		So(LookupPluralRule("nope"), ShouldBeNil)
+
+		en := LookupPluralRule("en-US")
+		So(en, ShouldNotEqual, nil)
+		So(en.Has(PluralOne), ShouldBeTrue)
+		So(en.Has(PluralFew), ShouldBeFalse)
+		So(en.Select(1), ShouldEqual, PluralOne)
+		So(en.Select(0), ShouldEqual, PluralOther)
+
+		ru := LookupPluralRule("ru_RU")
+		So(ru.Select(1), ShouldEqual, PluralOne)
+		So(ru.Select(21), ShouldEqual, PluralOne)
+		So(ru.Select(11), ShouldEqual, PluralMany)
+		So(ru.Select(3), ShouldEqual, PluralFew)
+		So(ru.Select(13), ShouldEqual, PluralMany)
+		So(ru.Select(5), ShouldEqual, PluralMany)
+
+		ar := LookupPluralRule("ar")
+		So(ar.Select(0), ShouldEqual, PluralZero)
+		So(ar.Select(2), ShouldEqual, PluralTwo)
+		So(ar.Select(103), ShouldEqual, PluralFew)
+		So(ar.Select(111), ShouldEqual, PluralMany)
+		So(ar.Select(100), ShouldEqual, PluralOther)
+
+		So(LookupPluralRule("fr").Select(0), ShouldEqual, PluralOne)
+		So(LookupPluralRule("fr").Has(PluralMany), ShouldBeTrue)
+		So(LookupPluralRule("fr").Select(2), ShouldEqual, PluralOther)
+		So(LookupPluralRule("fr").Select(1000000), ShouldEqual, PluralMany)
+		So(LookupPluralRule("es").Select(0), ShouldEqual, PluralOther)
+		So(LookupPluralRule("es").Select(2000000), ShouldEqual, PluralMany)
+		So(LookupPluralRule("it-IT").Has(PluralMany), ShouldBeTrue)
+
+		pt := LookupPluralRule("pt-BR")
+		So(pt.Select(0), ShouldEqual, PluralOne)
+		So(pt.Select(1), ShouldEqual, PluralOne)
+		So(pt.Select(1000000), ShouldEqual, PluralMany)
+		ptPT := LookupPluralRule("pt_PT")
+		So(ptPT.Select(0), ShouldEqual, PluralOther)
+		So(ptPT.Select(1), ShouldEqual, PluralOne)
+		So(ptPT.Select(1000000), ShouldEqual, PluralMany)
+		So(LookupPluralRule("PT-pt"), ShouldEqual, ptPT)
+		So(LookupPluralRule("ja").Select(1), ShouldEqual, PluralOther)
+	})
+
+	Convey("NewPlural", t, func() {
+		plural, err := NewPlural(1, map[PluralCategory]string{
+			PluralOne:   "%[2]s has one item",
+			PluralOther: "%[2]s has %[1]d items",
+		}, ".Count", ".Name")
+		So(err, ShouldEqual, nil)
+		So(plural, ShouldNotEqual, nil)
+
+		en := LookupPluralRule("en")
+		So(plural.Select(en, 1), ShouldEqual, "%[2]s has one item")
+		So(plural.Select(en, 2), ShouldEqual, "%[2]s has %[1]d items")
+		So(plural.Select(nil, 1), ShouldEqual, "%[2]s has %[1]d items")
+
+		result, err := plural.Sprintf(en, 1, "Bob")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "Bob has one item")
+		result, err = plural.Sprintf(en, uint8(3), "Bob")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "Bob has 3 items")
+		result, err = plural.Sprintf(nil, 3, "Bob")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "Bob has 3 items")
+		result, err = plural.Sprintf(en, "3", "Bob")
+		So(errors.Is(err, ErrInvalidPlural), ShouldBeTrue)
+		So(result, ShouldEqual, "")
+		result, err = plural.Sprintf(en)
+		So(errors.Is(err, ErrInvalidPlural), ShouldBeTrue)
+		So(result, ShouldEqual, "")
+
+		// cases without directives ignore the unused arguments
+		plural, err = NewPlural(1, map[PluralCategory]string{
+			PluralOne:   "one item",
+			PluralOther: "%d items",
+		})
+		So(err, ShouldEqual, nil)
+		result, err = plural.Sprintf(en, 1)
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "one item")
+		result, err = plural.Sprintf(en, 2)
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "2 items")
+
+		plural, err = NewPlural(1, map[PluralCategory]string{
+			PluralOne:   "%[2]s has one item",
+			PluralOther: "%[2]s has %[1]d items",
+		}, ".Count", ".Name")
+		So(err, ShouldEqual, nil)
+
+		variables, err := plural.Variables()
+		So(err, ShouldEqual, nil)
+		So(len(variables), ShouldEqual, 2)
+		So(variables[0].Label, ShouldEqual, "Count")
+
+		plural, err = NewPlural(1, map[PluralCategory]string{PluralOne: "one"})
+		So(errors.Is(err, ErrInvalidPlural), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "invalid plural: missing other case")
+		So(plural, ShouldBeNil)
+
+		_, err = NewPlural(1, map[PluralCategory]string{"lots": "%d", PluralOther: "%d"})
+		So(err.Error(), ShouldEqual, `invalid plural: unknown category "lots"`)
+
+		_, err = NewPlural(2, map[PluralCategory]string{PluralOther: "%d"})
+		So(err.Error(), ShouldEqual, "invalid plural: other case does not use argument 2")
+
+		_, err = NewPlural(1, map[PluralCategory]string{PluralOther: "%s"})
+		So(err.Error(), ShouldEqual, "invalid plural: argument 1 is not a number: %[1]s")
+
+		_, err = NewPlural(1, map[PluralCategory]string{PluralOne: "%d %s", PluralOther: "%d"})
+		So(err.Error(), ShouldEqual, "invalid plural: one case: unknown argument: %[2]s")
+
+		_, err = NewPlural(1, map[PluralCategory]string{PluralOne: "%[2]d", PluralOther: "%d %s"})
+		So(err.Error(), ShouldEqual, "invalid plural: one case: conflicting substitution types: %[2]s != %[2]d")
+
+		_, err = NewPlural(1, map[PluralCategory]string{PluralOne: "%!", PluralOther: "%d"})
+		So(err.Error(), ShouldEqual, "invalid plural: one case: invalid format at: %!")
+
+		_, err = NewPlural(1, map[PluralCategory]string{PluralOther: "%!"})
+		So(err.Error(), ShouldEqual, "invalid plural: other case: invalid format at: %!")
+	})
+}
@@ -0,0 +1,442 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPO = errors.New("invalid PO data")
+)
+
+const (
+	// POFlagGoFormat is the GNU gettext flag for Go format strings
+	POFlagGoFormat = "go-format"
+	// POFlagNoGoFormat is the GNU gettext flag for strings which are not Go
+	// format strings, even though they look like format strings
+	POFlagNoGoFormat = "no-go-format"
+	// POFlagFuzzy is the GNU gettext flag for translations which need review
+	POFlagFuzzy = "fuzzy"
+)
+
+// POFile is a GNU gettext PO or POT file, the header is the first entry
+// with an empty ID, when present
+type POFile struct {
+	Entries []*POEntry
+}
+
+// POEntry is a single GNU gettext PO entry
+type POEntry struct {
+	// TranslatorComments are the `# ` comment lines
+	TranslatorComments []string
+	// ExtractedComments are the `#.` comment lines
+	ExtractedComments []string
+	// References are the `#:` comment lines
+	References []string
+	// Flags are the comma separated `#,` flags
+	Flags []string
+	// Previous are the `#|` comment lines
+	Previous []string
+	// Context is the msgctxt
+	Context string
+	// ID is the msgid
+	ID string
+	// IDPlural is the msgid_plural
+	IDPlural string
+	// Str is the msgstr, or the list of msgstr[n] when IDPlural is not empty
+	Str []string
+	// Obsolete entries are written with the `#~` prefix
+	Obsolete bool
+}
+
+// NewPOEntry returns a new go-format PO entry for the given source Message,
+// with an extracted comment for each of the Variables listing the label,
+// verb and argv expression
+func NewPOEntry(source *Message) (entry *POEntry) {
+	entry = &POEntry{
+		ID:    source.Format,
+		Flags: []string{POFlagGoFormat},
+		Str:   []string{""},
+	}
+	for _, variable := range source.Variables {
+		comment := "{" + variable.Label + "}: " + variable.String()
+		if variable.Pos <= len(source.Argv) && source.Argv[variable.Pos-1] != "" {
+			comment += " " + source.Argv[variable.Pos-1]
+		}
+		entry.ExtractedComments = append(entry.ExtractedComments, comment)
+	}
+	return
+}
+
+// NewPOPluralEntry is the same as NewPOEntry except that the source Message
+// is used as the msgid_plural and the singular format string as the msgid
+func NewPOPluralEntry(singular string, source *Message) (entry *POEntry) {
+	entry = NewPOEntry(source)
+	entry.ID = singular
+	entry.IDPlural = source.Format
+	entry.Str = []string{"", ""}
+	return
+}
+
+// IsHeader returns true if this is the PO file header entry
+func (e *POEntry) IsHeader() (header bool) {
+	header = e.ID == "" && e.Context == "" && !e.Obsolete
+	return
+}
+
+// HasFlag returns true if the given flag is present
+func (e *POEntry) HasFlag(flag string) (present bool) {
+	for _, f := range e.Flags {
+		if present = f == flag; present {
+			return
+		}
+	}
+	return
+}
+
+// SetFlag adds or removes the given flag
+func (e *POEntry) SetFlag(flag string, enabled bool) {
+	var flags []string
+	for _, f := range e.Flags {
+		if f != flag {
+			flags = append(flags, f)
+		}
+	}
+	if enabled {
+		flags = append(flags, flag)
+	}
+	e.Flags = flags
+}
+
+// IsFuzzy returns true if the fuzzy flag is present
+func (e *POEntry) IsFuzzy() (fuzzy bool) {
+	fuzzy = e.HasFlag(POFlagFuzzy)
+	return
+}
+
+// Source returns the source Message of this entry, which is the IDPlural if
+// present, otherwise the ID
+func (e *POEntry) Source() (source *Message, err error) {
+	format := e.ID
+	if e.IDPlural != "" {
+		format = e.IDPlural
+	}
+	source, err = NewMessage(format)
+	return
+}
+
+// Validate decomposes the ID, IDPlural and all non-empty Str translations.
+// Only entries with the go-format flag (and without the no-go-format flag)
+// are validated, as the messages of other entries (ie: c-format or plain
+// text) are not Go format strings.
+//
+// For singular entries, each translation must be a valid translation of the
+// ID (see Message.Check). For plural entries, the IDPlural is the source and
+// the ID and translations may omit arguments (ie: "one item") but must
+// otherwise be compatible (see Variables.Compatible)
+func (e *POEntry) Validate() (err error) {
+	if e.IsHeader() || !e.HasFlag(POFlagGoFormat) || e.HasFlag(POFlagNoGoFormat) {
+		return
+	}
+
+	var source *Message
+	if source, err = e.Source(); err != nil {
+		err = fmt.Errorf("%w: %q: %w", ErrInvalidPO, e.ID, err)
+		return
+	}
+
+	if e.IDPlural == "" {
+		for _, str := range e.Str {
+			if str != "" {
+				if _, err = source.Check(str); err != nil {
+					return
+				}
+			}
+		}
+		return
+	}
+
+	for _, format := range append([]string{e.ID}, e.Str...) {
+		if format != "" {
+			var variables Variables
+			if _, _, variables, err = Decompose(format); err == nil {
+				err = source.Variables.Compatible(variables)
+			}
+			if err != nil {
+				err = fmt.Errorf("%w: %q: %w", ErrInvalidTranslation, format, err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// ReadPOFile parses the PO or POT data from the given reader
+func ReadPOFile(r io.Reader) (file *POFile, err error) {
+	file = &POFile{}
+
+	var entry *POEntry
+	var target *string  // the string continuation lines are appended to
+	var translated bool // the current entry has a msgstr
+
+	flush := func() {
+		if entry != nil {
+			file.Entries = append(file.Entries, entry)
+		}
+		entry, target, translated = nil, nil, false
+	}
+	current := func() *POEntry {
+		if entry == nil || translated {
+			flush()
+			entry = &POEntry{}
+		}
+		return entry
+	}
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		failed := func(reason string) error {
+			return fmt.Errorf("%w: line %d: %s: %v", ErrInvalidPO, number, reason, line)
+		}
+
+		if line == "" {
+			flush()
+			continue
+		}
+
+		var obsolete bool
+		if strings.HasPrefix(line, "#~") {
+			obsolete = true
+			line = strings.TrimSpace(line[2:])
+		}
+
+		if strings.HasPrefix(line, "#") {
+			e := current()
+			target = nil
+			switch {
+			case strings.HasPrefix(line, "#."):
+				e.ExtractedComments = append(e.ExtractedComments, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#:"):
+				e.References = append(e.References, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#,"):
+				for _, flag := range strings.Split(line[2:], ",") {
+					if flag = strings.TrimSpace(flag); flag != "" {
+						e.Flags = append(e.Flags, flag)
+					}
+				}
+			case strings.HasPrefix(line, "#|"):
+				e.Previous = append(e.Previous, strings.TrimSpace(line[2:]))
+			default:
+				e.TranslatorComments = append(e.TranslatorComments, strings.TrimPrefix(line[1:], " "))
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, `"`) {
+			if target == nil {
+				err = failed("unexpected string")
+				break
+			}
+			var value string
+			if value, err = unquotePO(line); err != nil {
+				err = failed(err.Error())
+				break
+			}
+			*target += value
+			continue
+		}
+
+		keyword, quoted, _ := strings.Cut(line, " ")
+		var value string
+		if value, err = unquotePO(strings.TrimSpace(quoted)); err != nil {
+			err = failed(err.Error())
+			break
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			e := current()
+			e.Context, target = value, &e.Context
+		case keyword == "msgid":
+			e := current()
+			e.ID, target = value, &e.ID
+		case keyword == "msgid_plural" && entry != nil && !translated:
+			entry.IDPlural, target = value, &entry.IDPlural
+		case keyword == "msgstr" && entry != nil:
+			entry.Str = append(entry.Str, value)
+			target = &entry.Str[len(entry.Str)-1]
+			translated = true
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]") && entry != nil:
+			if idx, ee := strconv.Atoi(keyword[7 : len(keyword)-1]); ee != nil || idx != len(entry.Str) {
+				err = failed("unexpected plural index")
+				break
+			}
+			entry.Str = append(entry.Str, value)
+			target = &entry.Str[len(entry.Str)-1]
+			translated = true
+		default:
+			err = failed("unexpected keyword")
+		}
+		if err != nil {
+			break
+		}
+		entry.Obsolete = entry.Obsolete || obsolete
+	}
+
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err != nil {
+		file = nil
+		return
+	}
+	flush()
+	return
+}
+
+// Validate returns the first POEntry.Validate error
+func (f *POFile) Validate() (err error) {
+	for _, entry := range f.Entries {
+		if err = entry.Validate(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Write writes the PO data to the given writer
+func (f *POFile) Write(w io.Writer) (err error) {
+	var buf strings.Builder
+	for idx, entry := range f.Entries {
+		if idx > 0 {
+			buf.WriteString("\n")
+		}
+		entry.write(&buf)
+	}
+	_, err = io.WriteString(w, buf.String())
+	return
+}
+
+func (e *POEntry) write(buf *strings.Builder) {
+	for _, comment := range e.TranslatorComments {
+		buf.WriteString(strings.TrimRight("# "+comment, " ") + "\n")
+	}
+	for _, comment := range e.ExtractedComments {
+		buf.WriteString("#. " + comment + "\n")
+	}
+	for _, reference := range e.References {
+		buf.WriteString("#: " + reference + "\n")
+	}
+	if len(e.Flags) > 0 {
+		buf.WriteString("#, " + strings.Join(e.Flags, ", ") + "\n")
+	}
+	for _, previous := range e.Previous {
+		buf.WriteString("#| " + previous + "\n")
+	}
+
+	var prefix string
+	if e.Obsolete {
+		prefix = "#~ "
+	}
+	if e.Context != "" {
+		buf.WriteString(prefix + "msgctxt " + quotePO(e.Context, prefix) + "\n")
+	}
+	buf.WriteString(prefix + "msgid " + quotePO(e.ID, prefix) + "\n")
+	if e.IDPlural != "" {
+		buf.WriteString(prefix + "msgid_plural " + quotePO(e.IDPlural, prefix) + "\n")
+		for idx, str := range e.Str {
+			buf.WriteString(prefix + "msgstr[" + strconv.Itoa(idx) + "] " + quotePO(str, prefix) + "\n")
+		}
+		return
+	}
+	var str string
+	if len(e.Str) > 0 {
+		str = e.Str[0]
+	}
+	buf.WriteString(prefix + "msgstr " + quotePO(str, prefix) + "\n")
+}
+
+// quotePO returns the PO string literal for the value given, values with
+// embedded newlines are written as multiple lines, one per newline
+func quotePO(value, prefix string) (quoted string) {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	lines := strings.SplitAfter(value, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 1 {
+		quoted = `"` + escape.Replace(value) + `"`
+		return
+	}
+	quoted = `""`
+	for _, line := range lines {
+		quoted += "\n" + prefix + `"` + escape.Replace(line) + `"`
+	}
+	return
+}
+
+// unquotePO returns the value of the PO string literal given
+func unquotePO(quoted string) (value string, err error) {
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		err = errors.New("invalid string")
+		return
+	}
+	quoted = quoted[1 : len(quoted)-1]
+	var buf strings.Builder
+	for i := 0; i < len(quoted); i++ {
+		c := quoted[i]
+		if c != '\\' {
+			if c == '"' {
+				err = errors.New("unescaped quote")
+				return
+			}
+			buf.WriteByte(c)
+			continue
+		} else if i += 1; i >= len(quoted) {
+			err = errors.New("invalid escape")
+			return
+		}
+		switch quoted[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 't':
+			buf.WriteByte('\t')
+		case 'r':
+			buf.WriteByte('\r')
+		case 'a':
+			buf.WriteByte('\a')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'v':
+			buf.WriteByte('\v')
+		case '\\', '"':
+			buf.WriteByte(quoted[i])
+		default:
+			err = fmt.Errorf("invalid escape: \\%c", quoted[i])
+			return
+		}
+	}
+	value = buf.String()
+	return
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testPO = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+# a translator comment
+#. {Name}: %[1]s .Name
+#. {Count}: %[2]d .Count
+#: templates/cart.tmpl:12
+#, fuzzy, go-format
+msgctxt "cart"
+msgid "%s has %d items"
+msgstr "%[2]d Artikel gehören %[1]s"
+
+#, go-format
+msgid "one item"
+msgid_plural "%d items"
+msgstr[0] "ein Artikel"
+msgstr[1] "%d Artikel"
+
+#~ msgid "old \"quoted\"\tthing"
+#~ msgstr "alt"
+`
+
+func TestPO(t *testing.T) {
+	Convey("ReadPOFile", t, func() {
+		file, err := ReadPOFile(strings.NewReader(testPO))
+		So(err, ShouldEqual, nil)
+		So(len(file.Entries), ShouldEqual, 4)
+
+		header := file.Entries[0]
+		So(header.IsHeader(), ShouldBeTrue)
+		So(header.Str[0], ShouldEqual, "Language: de\nPlural-Forms: nplurals=2; plural=(n != 1);\n")
+
+		entry := file.Entries[1]
+		So(entry.IsHeader(), ShouldBeFalse)
+		So(entry.TranslatorComments, ShouldResemble, []string{"a translator comment"})
+		So(entry.ExtractedComments, ShouldResemble, []string{"{Name}: %[1]s .Name", "{Count}: %[2]d .Count"})
+		So(entry.References, ShouldResemble, []string{"templates/cart.tmpl:12"})
+		So(entry.Flags, ShouldResemble, []string{"fuzzy", "go-format"})
+		So(entry.IsFuzzy(), ShouldBeTrue)
+		So(entry.Context, ShouldEqual, "cart")
+		So(entry.ID, ShouldEqual, "%s has %d items")
+		So(entry.Str, ShouldResemble, []string{"%[2]d Artikel gehören %[1]s"})
+
+		entry = file.Entries[2]
+		So(entry.ID, ShouldEqual, "one item")
+		So(entry.IDPlural, ShouldEqual, "%d items")
+		So(entry.Str, ShouldResemble, []string{"ein Artikel", "%d Artikel"})
+
+		entry = file.Entries[3]
+		So(entry.Obsolete, ShouldBeTrue)
+		So(entry.ID, ShouldEqual, "old \"quoted\"\tthing")
+
+		So(file.Validate(), ShouldEqual, nil)
+
+		var buf bytes.Buffer
+		So(file.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, testPO)
+
+		_, err = ReadPOFile(strings.NewReader(`msgid "one`))
+		So(errors.Is(err, ErrInvalidPO), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, `invalid PO data: line 1: invalid string: msgid "one`)
+
+		_, err = ReadPOFile(strings.NewReader("\"orphan\"\n"))
+		So(err.Error(), ShouldEqual, `invalid PO data: line 1: unexpected string: "orphan"`)
+
+		_, err = ReadPOFile(strings.NewReader("msgid \"x\"\nmsgid_plural \"y\"\nmsgstr[1] \"z\"\n"))
+		So(err.Error(), ShouldEqual, `invalid PO data: line 3: unexpected plural index: msgstr[1] "z"`)
+
+		_, err = ReadPOFile(strings.NewReader("msgstr \"x\"\n"))
+		So(err.Error(), ShouldEqual, `invalid PO data: line 1: unexpected keyword: msgstr "x"`)
+
+		_, err = ReadPOFile(strings.NewReader("msgid \"\\q\"\n"))
+		So(err.Error(), ShouldEqual, `invalid PO data: line 1: invalid escape: \q: msgid "\q"`)
+	})
+
+	Convey("Validate", t, func() {
+		source, err := NewMessage("%s has %d items", ".Name", ".Count")
+		So(err, ShouldEqual, nil)
+
+		entry := NewPOEntry(source)
+		So(entry.HasFlag(POFlagGoFormat), ShouldBeTrue)
+		So(entry.ExtractedComments, ShouldResemble, []string{"{Name}: %[1]s .Name", "{Count}: %[2]d .Count"})
+		So(entry.Validate(), ShouldEqual, nil)
+
+		entry.Str[0] = "%[2]d Artikel"
+		So(errors.Is(entry.Validate(), ErrInvalidTranslation), ShouldBeTrue)
+		entry.SetFlag(POFlagNoGoFormat, true)
+		So(entry.Validate(), ShouldEqual, nil)
+		entry.SetFlag(POFlagNoGoFormat, false)
+		So(entry.HasFlag(POFlagNoGoFormat), ShouldBeFalse)
+
+		entry = &POEntry{ID: "%!", Str: []string{""}, Flags: []string{POFlagGoFormat}}
+		So(errors.Is(entry.Validate(), ErrInvalidPO), ShouldBeTrue)
+
+		// entries without the go-format flag are not Go format strings
+		entry = &POEntry{ID: "%lu files", Str: []string{"%lu Dateien"}, Flags: []string{"c-format"}}
+		So(entry.Validate(), ShouldEqual, nil)
+		entry = &POEntry{ID: "50% off", Str: []string{"50% Rabatt"}}
+		So(entry.Validate(), ShouldEqual, nil)
+		So((&POFile{Entries: []*POEntry{entry}}).Validate(), ShouldEqual, nil)
+
+		plural, _ := NewMessage("%[2]s has %[1]d items", ".Count", ".Name")
+		entry = NewPOPluralEntry("%[2]s has one item", plural)
+		So(entry.Validate(), ShouldEqual, nil)
+		entry.Str = []string{"%[2]s hat einen Artikel", "%[2]s hat %[1]d Artikel"}
+		So(entry.Validate(), ShouldEqual, nil)
+		entry.Str[0] = "%[2]s hat %[3]d"
+		So(errors.Is(entry.Validate(), ErrInvalidTranslation), ShouldBeTrue)
+
+		var buf bytes.Buffer
+		So((&POFile{Entries: []*POEntry{entry}}).Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, `#. {Count}: %[1]d .Count
+#. {Name}: %[2]s .Name
+#, go-format
+msgid "%[2]s has one item"
+msgid_plural "%[2]s has %[1]d items"
+msgstr[0] "%[2]s hat %[3]d"
+msgstr[1] "%[2]s hat %[1]d Artikel"
+`)
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrPseudoMismatch = errors.New("pseudo-localized variables mismatch")
+)
+
+// PseudoMode is a set of pseudo-localization transformations
+type PseudoMode uint8
+
+const (
+	// PseudoAccent replaces ASCII letters with accented look-alikes
+	PseudoAccent PseudoMode = 1 << iota
+	// PseudoExpand pads the literal text by approximately thirty percent
+	PseudoExpand
+	// PseudoBracket surrounds the result with square brackets
+	PseudoBracket
+	// PseudoMirror wraps each literal text segment in the Unicode
+	// right-to-left override and pop directional formatting characters
+	PseudoMirror
+
+	// PseudoAll is all of the pseudo-localization transformations
+	PseudoAll = PseudoAccent | PseudoExpand | PseudoBracket | PseudoMirror
+)
+
+const (
+	gPseudoRLO = "\u202e"
+	gPseudoPDF = "\u202c"
+)
+
+var gPseudoAccents = func() (accents map[rune]rune) {
+	accents = make(map[rune]rune)
+	ascii := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	accented := []rune("àƀçđéƒĝĥîĵķļɱñöþǫŕšţûṽŵẋýžÀƁÇĐÉƑĜĤÎĴĶĻṀÑÖÞǪŔŠŢÛṼŴẊÝŽ")
+	for idx, r := range ascii {
+		accents[r] = accented[idx]
+	}
+	return
+}()
+
+// Has returns true if all of the given modes are present
+func (m PseudoMode) Has(mode PseudoMode) (present bool) {
+	present = m&mode == mode
+	return
+}
+
+// Pseudolocalize returns a pseudo-localized version of the given format
+// string, for testing layouts before real translations exist. Only the
+// literal text of the format string is transformed, the directives and `%%`
+// escapes are left intact and Pseudolocalize returns an ErrPseudoMismatch
+// error if the result does not decompose to identical Variables.
+//
+// The mode selects the transformations applied: PseudoAccent replaces ASCII
+// letters with accented look-alikes (ie: `Hello` becomes `Ĥéļļö`),
+// PseudoExpand appends tildes to the literal text to grow it by roughly
+// thirty percent, PseudoBracket surrounds the result with `[` and `]` to
+// reveal truncation and PseudoMirror wraps the literal text in right-to-left
+// overrides to simulate right-to-left languages
+func Pseudolocalize(format string, mode PseudoMode) (pseudo string, err error) {
+	var segments Segments
+	var variables Variables
+	if segments, variables, err = Parse(format); err != nil {
+		return
+	}
+
+	var length int
+	var buf strings.Builder
+	for _, segment := range segments {
+		if segment.Variable != nil {
+			buf.WriteString(segment.Variable.Source)
+			continue
+		}
+		length += utf8.RuneCountInString(segment.Text)
+		buf.WriteString(pseudoText(segment.Text, mode))
+	}
+
+	if mode.Has(PseudoExpand) && length > 0 {
+		buf.WriteString(pseudoText(strings.Repeat("~", (length*3+9)/10), mode&^PseudoAccent))
+	}
+
+	pseudo = buf.String()
+	if mode.Has(PseudoBracket) {
+		pseudo = "[" + pseudo + "]"
+	}
+
+	var check Variables
+	if _, check, err = Parse(pseudo); err != nil {
+		err = fmt.Errorf("%w: %w", ErrPseudoMismatch, err)
+	} else if !reflect.DeepEqual(variables, check) {
+		err = fmt.Errorf("%w: %v != %v", ErrPseudoMismatch, variables, check)
+	}
+	if err != nil {
+		pseudo = ""
+	}
+	return
+}
+
+// pseudoText returns the escaped and transformed literal text
+func pseudoText(text string, mode PseudoMode) (transformed string) {
+	if mode.Has(PseudoAccent) {
+		text = strings.Map(func(r rune) rune {
+			if accented, present := gPseudoAccents[r]; present {
+				return accented
+			}
+			return r
+		}, text)
+	}
+	transformed = escapeText(text)
+	if mode.Has(PseudoMirror) && strings.TrimSpace(text) != "" {
+		transformed = gPseudoRLO + transformed + gPseudoPDF
+	}
+	return
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPseudolocalize(t *testing.T) {
+	Convey("Modes", t, func() {
+		format := "Hello %[1]s, 100%% of %-5.2f!"
+
+		pseudo, err := Pseudolocalize(format, 0)
+		So(err, ShouldEqual, nil)
+		So(pseudo, ShouldEqual, format)
+
+		pseudo, err = Pseudolocalize(format, PseudoAccent)
+		So(err, ShouldEqual, nil)
+		So(pseudo, ShouldEqual, "Ĥéļļö %[1]s, 100%% öƒ %-5.2f!")
+
+		pseudo, err = Pseudolocalize(format, PseudoExpand)
+		So(err, ShouldEqual, nil)
+		So(pseudo, ShouldEqual, "Hello %[1]s, 100%% of %-5.2f!~~~~~~")
+
+		pseudo, err = Pseudolocalize(format, PseudoBracket)
+		So(err, ShouldEqual, nil)
+		So(pseudo, ShouldEqual, "[Hello %[1]s, 100%% of %-5.2f!]")
+
+		pseudo, err = Pseudolocalize("%s and %d", PseudoMirror)
+		So(err, ShouldEqual, nil)
+		So(pseudo, ShouldEqual, "%s\u202e and \u202c%d")
+
+		pseudo, err = Pseudolocalize("Hi %s", PseudoAll)
+		So(err, ShouldEqual, nil)
+		So(pseudo, ShouldEqual, "[\u202eĤî \u202c%s\u202e~\u202c]")
+
+		So(PseudoAll.Has(PseudoMirror|PseudoAccent), ShouldBeTrue)
+		So(PseudoAccent.Has(PseudoMirror), ShouldBeFalse)
+	})
+
+	Convey("Errors", t, func() {
+		_, err := Pseudolocalize("%[1]d %[1]s", PseudoAll)
+		So(err, ShouldNotEqual, nil)
+		So(errors.Is(err, ErrPseudoMismatch), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidMapping = errors.New("invalid argument mapping")
+)
+
+// Reindex rewrites every argument position used within the given format
+// string according to the `mapping` given, where the keys are the current
+// positions and the values are the new positions (both starting at 1).
+//
+// The mapping must be a bijection over the positions used by the format
+// string: every used position must have a mapping and no two used positions
+// may be mapped to the same new position. Mappings for positions not used by
+// the format string are ignored. Reindex returns an ErrInvalidMapping error
+// if the mapping is not valid.
+//
+// The resulting format string uses explicit argument indexes only where
+// necessary, see Normalize. Note that explicit argument indexes for the
+// width and precision flags are not supported by Decompose yet and so are
+// not supported by Reindex either.
+func Reindex(format string, mapping map[int]int) (reindexed string, err error) {
+	var segments Segments
+	if segments, _, err = Parse(format); err != nil {
+		return
+	}
+
+	targets := make(map[int]int)
+	for _, variable := range segments.Variables() {
+		target, present := mapping[variable.Pos]
+		if !present {
+			err = fmt.Errorf("%w: position %d is not mapped", ErrInvalidMapping, variable.Pos)
+			return
+		} else if target < 1 {
+			err = fmt.Errorf("%w: position %d is mapped to %d", ErrInvalidMapping, variable.Pos, target)
+			return
+		} else if other, found := targets[target]; found && other != variable.Pos {
+			err = fmt.Errorf("%w: positions %d and %d are both mapped to %d", ErrInvalidMapping, other, variable.Pos, target)
+			return
+		}
+		targets[target] = variable.Pos
+	}
+
+	for _, variable := range segments.Variables() {
+		variable.Pos = mapping[variable.Pos]
+	}
+
+	reindexed = segments.String()
+	return
+}
+
+// Shift moves every argument position used within the given format string
+// by the given `delta`. A positive delta is useful when arguments are being
+// prepended to the argument list, for example: shifting `%s %d` by one
+// results in `%[2]s %d`. Shift returns an ErrInvalidMapping error if any
+// position would be moved below the first position
+func Shift(format string, delta int) (shifted string, err error) {
+	var variables Variables
+	if _, variables, err = Parse(format); err != nil {
+		return
+	}
+	mapping := make(map[int]int)
+	for _, variable := range variables {
+		mapping[variable.Pos] = variable.Pos + delta
+	}
+	shifted, err = Reindex(format, mapping)
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReindex(t *testing.T) {
+	Convey("Reindex", t, func() {
+		reindexed, err := Reindex("", nil)
+		So(err, ShouldEqual, nil)
+		So(reindexed, ShouldEqual, "")
+
+		reindexed, err = Reindex("%s has %d items", map[int]int{1: 2, 2: 1})
+		So(err, ShouldEqual, nil)
+		So(reindexed, ShouldEqual, "%[2]s has %[1]d items")
+
+		reindexed, err = Reindex("%s %[1]s %d", map[int]int{1: 3, 2: 1, 9: 9})
+		So(err, ShouldEqual, nil)
+		So(reindexed, ShouldEqual, "%[3]s %[3]s %[1]d")
+
+		reindexed, err = Reindex("%s has %d items", map[int]int{1: 2})
+		So(errors.Is(err, ErrInvalidMapping), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "invalid argument mapping: position 2 is not mapped")
+		So(reindexed, ShouldEqual, "")
+
+		reindexed, err = Reindex("%s has %d items", map[int]int{1: 2, 2: 2})
+		So(errors.Is(err, ErrInvalidMapping), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "invalid argument mapping: positions 1 and 2 are both mapped to 2")
+		So(reindexed, ShouldEqual, "")
+
+		reindexed, err = Reindex("%s", map[int]int{1: 0})
+		So(errors.Is(err, ErrInvalidMapping), ShouldBeTrue)
+		So(reindexed, ShouldEqual, "")
+
+		reindexed, err = Reindex("%d %[1]s", map[int]int{1: 1})
+		So(err, ShouldNotEqual, nil)
+		So(reindexed, ShouldEqual, "")
+	})
+
+	Convey("Shift", t, func() {
+		shifted, err := Shift("%s has %d items", 1)
+		So(err, ShouldEqual, nil)
+		So(shifted, ShouldEqual, "%[2]s has %d items")
+
+		shifted, err = Shift("%[3]s has %[2]d items", -1)
+		So(err, ShouldEqual, nil)
+		So(shifted, ShouldEqual, "%[2]s has %[1]d items")
+
+		shifted, err = Shift("%s has %d items", -1)
+		So(errors.Is(err, ErrInvalidMapping), ShouldBeTrue)
+		So(shifted, ShouldEqual, "")
+	})
+}
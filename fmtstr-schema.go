@@ -0,0 +1,114 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the generated schemas
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema used to describe the named
+// arguments of a Message, see NewJSONSchema
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	// Width is the minimum formatted width of the argument, which is a hint
+	// for clients and is not a JSON Schema validation keyword
+	Width int `json:"x-width,omitempty"`
+	// Precision is the formatted precision of the argument, which is a hint
+	// for clients and is not a JSON Schema validation keyword
+	Precision *int `json:"x-precision,omitempty"`
+}
+
+// NewJSONSchema returns the JSON Schema object describing the named arguments
+// of the given Message, where each of the Variables is a required property
+// named by its Label, with a type derived from its Variable Type: num is an
+// integer, float is a number, text is a string, bool is a boolean and any is
+// left untyped, as are the %p, %x and %X verbs which accept several kinds of
+// values. The Variable Source is the property description and the Width and
+// Precision are included as the `x-width` and `x-precision` hints
+func NewJSONSchema(message *Message) (schema *JSONSchema) {
+	closed := false
+	schema = &JSONSchema{
+		Schema:               JSONSchemaDraft,
+		Title:                message.Format,
+		Description:          message.Labelled,
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: &closed,
+	}
+	for _, variable := range message.Variables {
+		name := variable.Label
+		if name == "" {
+			name = variable.Key()
+		}
+		if _, present := schema.Properties[name]; present {
+			continue
+		}
+		schema.Properties[name] = variable.JSONSchema()
+		schema.Required = append(schema.Required, name)
+	}
+	return
+}
+
+// JSONSchema returns the JSON Schema of the argument of this Variable, see
+// NewJSONSchema
+func (v *Variable) JSONSchema() (schema *JSONSchema) {
+	schema = &JSONSchema{
+		Description: v.Source,
+		Width:       v.Width,
+	}
+	switch v.Type {
+	case "num":
+		switch v.Verb {
+		case "p", "x", "X":
+			// these also accept pointers, strings and byte slices
+		default:
+			schema.Type = "integer"
+		}
+	case "float":
+		schema.Type = "number"
+	case "text":
+		schema.Type = "string"
+	case "bool":
+		schema.Type = "boolean"
+	}
+	if v.Has(ModDecimal) {
+		precision := v.Precision
+		schema.Precision = &precision
+	}
+	return
+}
+
+// String returns the JSON encoding of the JSONSchema, using two-space
+// indentation
+func (s *JSONSchema) String() (value string) {
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s); err == nil {
+		value = strings.TrimSuffix(buf.String(), "\n")
+	}
+	return
+}
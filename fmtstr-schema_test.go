@@ -0,0 +1,87 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJSONSchema(t *testing.T) {
+	Convey("NewJSONSchema", t, func() {
+		message, err := NewMessage("%s has %5d items worth %.2f (%t, %v, %[2]x)", ".Name", ".Count", ".Total", ".Paid", ".Extra")
+		So(err, ShouldEqual, nil)
+		schema := NewJSONSchema(message)
+		So(schema.Schema, ShouldEqual, JSONSchemaDraft)
+		So(schema.Type, ShouldEqual, "object")
+		So(schema.Title, ShouldEqual, message.Format)
+		So(schema.Description, ShouldEqual, "{Name} has {Count} items worth {Total} ({Paid}, {Extra}, {Count})")
+		So(schema.Required, ShouldResemble, []string{"Name", "Count", "Total", "Paid", "Extra"})
+		So(*schema.AdditionalProperties, ShouldBeFalse)
+
+		So(schema.Properties["Name"].Type, ShouldEqual, "string")
+		So(schema.Properties["Count"].Type, ShouldEqual, "integer")
+		So(schema.Properties["Count"].Width, ShouldEqual, 5)
+		So(schema.Properties["Count"].Precision, ShouldBeNil)
+		So(schema.Properties["Total"].Type, ShouldEqual, "number")
+		So(*schema.Properties["Total"].Precision, ShouldEqual, 2)
+		So(schema.Properties["Paid"].Type, ShouldEqual, "boolean")
+		So(schema.Properties["Extra"].Type, ShouldEqual, "")
+
+		var decoded map[string]interface{}
+		So(json.Unmarshal([]byte(schema.String()), &decoded), ShouldEqual, nil)
+		So(decoded["$schema"], ShouldEqual, JSONSchemaDraft)
+		So(decoded["additionalProperties"], ShouldEqual, false)
+		So(decoded["properties"], ShouldResemble, map[string]interface{}{
+			"Name":  map[string]interface{}{"description": "%s", "type": "string"},
+			"Count": map[string]interface{}{"description": "%5d", "type": "integer", "x-width": 5.0},
+			"Total": map[string]interface{}{"description": "%.2f", "type": "number", "x-precision": 2.0},
+			"Paid":  map[string]interface{}{"description": "%t", "type": "boolean"},
+			"Extra": map[string]interface{}{"description": "%v"},
+		})
+	})
+
+	Convey("empty", t, func() {
+		message, err := NewMessage("no arguments")
+		So(err, ShouldEqual, nil)
+		So(NewJSONSchema(message).String(), ShouldEqual, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "no arguments",
+  "description": "no arguments",
+  "type": "object",
+  "additionalProperties": false
+}`)
+	})
+
+	Convey("dialect types and pointers", t, func() {
+		_, variables, err := CDialect.Parse("%a at %p")
+		So(err, ShouldEqual, nil)
+		So(variables[0].JSONSchema().Type, ShouldEqual, "number")
+		So(variables[1].JSONSchema().Type, ShouldEqual, "")
+
+		_, variables, err = Parse("%p %x %X")
+		So(err, ShouldEqual, nil)
+		So(variables[0].JSONSchema().Type, ShouldEqual, "")
+		So(variables[1].JSONSchema().Type, ShouldEqual, "")
+		So(variables[2].JSONSchema().Type, ShouldEqual, "")
+	})
+
+	Convey("precision without digits", t, func() {
+		schema := (&Variable{Pos: 1, Verb: "f", Modifiers: ModDecimal, Source: "%.f"}).JSONSchema()
+		So(*schema.Precision, ShouldEqual, 0)
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Segment is one part of a parsed format string, either a substitution
+// Variable or the literal Text between substitution Variables. The Text is
+// stored without any format string escaping, for example: a literal `%%` in
+// the format string is a `%` in the Text
+type Segment struct {
+	Text     string
+	Variable *Variable
+}
+
+func newTextSegment(source string) (segment *Segment) {
+	segment = &Segment{Text: strings.ReplaceAll(source, "%%", "%")}
+	return
+}
+
+// IsVariable returns true if this Segment is a substitution Variable
+func (s *Segment) IsVariable() (variable bool) {
+	variable = s.Variable != nil
+	return
+}
+
+// Segments is a list of Segment instances, in the order of the format string
+// that was parsed
+type Segments []*Segment
+
+// Variables returns the list of all substitution Variables, in the order in
+// which they appear, including duplicate references to the same position
+func (s Segments) Variables() (variables Variables) {
+	for _, segment := range s {
+		if segment.Variable != nil {
+			variables = append(variables, segment.Variable)
+		}
+	}
+	return
+}
+
+// String returns the fmt format string for these Segments, using explicit
+// argument indexes only where the implicit argument order would not produce
+// the correct argument
+func (s Segments) String() (format string) {
+	next := 1 // next is the implicit argument position
+	for _, segment := range s {
+		if segment.Variable == nil {
+			format += escapeText(segment.Text)
+			continue
+		}
+		format += segment.Variable.directive(segment.Variable.Pos != next)
+		next = segment.Variable.Pos + 1
+	}
+	return
+}
+
+// Replaced returns the fmt format string for these Segments, using explicit
+// argument indexes for every substitution Variable
+func (s Segments) Replaced() (format string) {
+	for _, segment := range s {
+		if segment.Variable == nil {
+			format += escapeText(segment.Text)
+			continue
+		}
+		format += segment.Variable.String()
+	}
+	return
+}
+
+// Sprintf formats each of the substitution Variables with its argument from
+// the list given and returns the result. Unlike fmt.Sprintf with the Replaced
+// format string, arguments which are not used by any of the Variables are
+// ignored instead of being reported as `%!(EXTRA ...)`
+func (s Segments) Sprintf(argv ...interface{}) (result string) {
+	var buf strings.Builder
+	for _, segment := range s {
+		if segment.Variable == nil {
+			buf.WriteString(segment.Text)
+			continue
+		}
+		// an explicit index never reports extra arguments
+		buf.WriteString(fmt.Sprintf(segment.Variable.String(), argv...))
+	}
+	result = buf.String()
+	return
+}
+
+// Labelled returns the format string with all substitution Variables
+// replaced with their curly-braced labels
+func (s Segments) Labelled() (labelled string) {
+	for _, segment := range s {
+		if segment.Variable == nil {
+			labelled += escapeText(segment.Text)
+			continue
+		}
+		labelled += "{" + segment.Variable.Label + "}"
+	}
+	return
+}
+
+func escapeText(text string) (escaped string) {
+	escaped = strings.ReplaceAll(text, "%", "%%")
+	return
+}
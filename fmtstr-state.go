@@ -38,6 +38,16 @@ type cState struct {
 	width     string
 	precision string
 
+	// length is the C length modifier, ie: the `ll` in %lld
+	length string
+	// conversion is the C conversion when it differs from the verb, ie: the
+	// `u` in %zu
+	conversion string
+	// valueType overrides the verb.Type, ie: the C %a is a Go %x of a float
+	valueType string
+	// name is the named argument key, ie: the `name` in Python's %(name)s
+	name string
+
 	source string
 }
 
@@ -80,7 +90,7 @@ func (s *cState) updatePMHS(r rune, char string) {
 }
 
 func (s *cState) updateDigitFlag(r rune, char string) {
-	if r == '0' && s.width == "" && s.precision == "" {
+	if r == '0' && !s.decimal && s.width == "" {
 		s.zero = true
 	} else if s.decimal {
 		s.precision += char
@@ -94,9 +104,18 @@ func (s *cState) make(argv []string) (variable *Variable) {
 	var label, valueType string
 	valueType = s.verb.Type()
 	label = s.verb.Label()
+	if s.valueType != "" {
+		valueType = s.valueType
+		label = strcase.ToCamel(valueType)
+	}
 
 	var arg string
-	if len(argv) >= s.pos {
+	if s.name != "" {
+		// named arguments are labelled by their names
+		if arg = strcase.ToCamel(strings.TrimTmplVar(s.name)); arg != "" {
+			label = arg
+		}
+	} else if len(argv) >= s.pos {
 		// pos is within argv range, make label from argv[pos-1]
 		arg = strings.TrimTmplVar(argv[s.pos-1])
 		if arg = strcase.ToCamel(arg); arg != "" {
@@ -113,13 +132,60 @@ func (s *cState) make(argv []string) (variable *Variable) {
 	}
 
 	return &Variable{
-		Type:      valueType,
-		Label:     label,
-		Source:    s.source,
-		Pos:       s.pos,
-		Verb:      s.verb,
-		Width:     width,
-		Precision: precision,
-		Modifiers: s.modifiers(),
+		Type:       valueType,
+		Label:      label,
+		Source:     s.source,
+		Pos:        s.pos,
+		Verb:       s.verb,
+		Width:      width,
+		Precision:  precision,
+		Modifiers:  s.modifiers(),
+		Length:     s.length,
+		Conversion: s.conversion,
+		Name:       s.name,
+	}
+}
+
+// positionNamed sets the argument positions of the named Variables given,
+// which are in the order of their first appearance. Names matching the label
+// of an argv entry above the highest position already used take the argv
+// position, so that formats converted to named arguments convert back with
+// the same argument order. All other names take the next free positions
+func positionNamed(named []*Variable, argv []string, highest int) {
+	labels := make(map[string]int)
+	for idx := highest; idx < len(argv); idx++ {
+		if label := strcase.ToCamel(strings.TrimTmplVar(argv[idx])); label != "" {
+			if _, present := labels[label]; !present {
+				labels[label] = idx + 1
+			}
+		}
+	}
+
+	names := make(map[string]int)
+	taken := make(map[int]struct{})
+	for _, variable := range named {
+		if _, present := names[variable.Name]; !present {
+			if pos, found := labels[strcase.ToCamel(variable.Name)]; found {
+				if _, used := taken[pos]; !used {
+					names[variable.Name] = pos
+					taken[pos] = struct{}{}
+				}
+			}
+		}
+	}
+
+	next := highest
+	for _, variable := range named {
+		pos, present := names[variable.Name]
+		if !present {
+			for next += 1; ; next += 1 {
+				if _, used := taken[next]; !used {
+					break
+				}
+			}
+			pos = next
+			names[variable.Name] = pos
+		}
+		variable.Pos = pos
 	}
 }
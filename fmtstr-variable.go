@@ -15,7 +15,12 @@
 package fmtstr
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
 )
 
 type Modifier uint8
@@ -39,10 +44,36 @@ type Variable struct {
 	Width     int
 	Precision int
 	Modifiers Modifier
+	// Length is the C length modifier, ie: the `ll` in %lld, and is not
+	// used by the Go dialect
+	Length string
+	// Conversion is the C conversion when it differs from the Verb, ie: the
+	// `u` in %zu, and is not used by the Go dialect
+	Conversion string
+	// Name is the named argument key, ie: the `name` in Python's %(name)s,
+	// and is not used by the Go dialect
+	Name string
 }
 
 func (v *Variable) String() (value string) {
-	value = "%"
+	value = v.directive(true)
+	return
+}
+
+// directive returns the fmt format string for this Variable, including the
+// explicit argument index only when requested
+func (v *Variable) directive(explicit bool) (value string) {
+	value = "%" + v.flags()
+	if explicit {
+		value += "[" + strconv.Itoa(v.Pos) + "]"
+	}
+	value += v.Verb.String()
+	return
+}
+
+// flags returns the modifiers, width and precision of this Variable, in the
+// fmt format string order
+func (v *Variable) flags() (value string) {
 	if v.Has(ModHash) {
 		value += "#"
 	}
@@ -67,12 +98,69 @@ func (v *Variable) String() (value string) {
 			value += strconv.Itoa(v.Precision)
 		}
 	}
-	value += "[" + strconv.Itoa(v.Pos) + "]"
-	value += v.Verb.String()
+	return
+}
+
+// Key returns the Name of this Variable if present, otherwise a snake_cased
+// version of the Label, or "arg" with the Pos suffixed if there is no Label
+func (v *Variable) Key() (key string) {
+	if key = v.Name; key == "" {
+		if key = strcase.ToSnake(v.Label); key == "" {
+			key = "arg" + strconv.Itoa(v.Pos)
+		}
+	}
+	return
+}
+
+// Example returns an example of this Variable's formatted output, made by
+// formatting a sample value of the Variable Type: 42 for num, 3.14159 for
+// float, true for bool, an error of the Label for error and the Label for
+// text (and any). Example returns an empty string when the sample value is
+// not valid for the Verb
+func (v *Variable) Example() (example string) {
+	var sample interface{}
+	switch v.Type {
+	case "num":
+		sample = 42
+	case "float":
+		sample = 3.14159
+	case "bool":
+		sample = true
+	case "error":
+		// only fmt.Errorf supports the %w verb
+		example = fmt.Errorf(v.directive(false), errors.New(v.Label)).Error()
+	default:
+		sample = v.Label
+	}
+	if sample != nil {
+		example = fmt.Sprintf(v.directive(false), sample)
+	}
+	if strings.Contains(example, "%!") {
+		example = ""
+	}
 	return
 }
 
 func (v *Variable) Has(m Modifier) (present bool) {
-	present = v.Modifiers&m == m
+	present = v.Modifiers.Has(m)
+	return
+}
+
+// Validate returns an ErrInvalidVariable error if the Variable has no
+// argument position or verb, a negative Width or Precision, or a Precision
+// without the ModDecimal flag
+func (v *Variable) Validate() (err error) {
+	switch {
+	case v.Pos < 1:
+		err = fmt.Errorf("%w: position %d", ErrInvalidVariable, v.Pos)
+	case v.Verb == "":
+		err = fmt.Errorf("%w: missing verb", ErrInvalidVariable)
+	case v.Width < 0:
+		err = fmt.Errorf("%w: negative width %d", ErrInvalidVariable, v.Width)
+	case v.Precision < 0:
+		err = fmt.Errorf("%w: negative precision %d", ErrInvalidVariable, v.Precision)
+	case v.Precision > 0 && !v.Has(ModDecimal):
+		err = fmt.Errorf("%w: precision %d without the %s modifier", ErrInvalidVariable, v.Precision, ModDecimal)
+	}
 	return
 }
@@ -15,6 +15,7 @@
 package fmtstr
 
 import (
+	"errors"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -31,4 +32,29 @@ func TestVariable(t *testing.T) {
 		}
 		So(v.String(), ShouldEqual, "%[1]s")
 	})
+	Convey("Example", t, func() {
+		So((&Variable{Type: "num", Verb: "d", Width: 4, Modifiers: ModZeroPad}).Example(), ShouldEqual, "0042")
+		So((&Variable{Type: "float", Verb: "f", Precision: 2, Modifiers: ModDecimal}).Example(), ShouldEqual, "3.14")
+		So((&Variable{Type: "text", Label: "Name", Verb: "q"}).Example(), ShouldEqual, `"Name"`)
+		So((&Variable{Type: "bool", Verb: "t"}).Example(), ShouldEqual, "true")
+		So((&Variable{Type: "error", Label: "Cause", Verb: "w"}).Example(), ShouldEqual, "Cause")
+		So((&Variable{Type: "num", Verb: "p"}).Example(), ShouldEqual, "")
+	})
+	Convey("Validate", t, func() {
+		_, _, variables, err := Decompose("%[1]s %+08.2[2]f %.[3]f %-5[4]d %#[5]x %[6]t")
+		So(err, ShouldEqual, nil)
+		for _, variable := range variables {
+			So(variable.Validate(), ShouldEqual, nil)
+		}
+
+		for _, v := range []*Variable{
+			{Verb: "d"},
+			{Pos: 1},
+			{Pos: 1, Verb: "d", Width: -1},
+			{Pos: 1, Verb: "f", Precision: -1, Modifiers: ModDecimal},
+			{Pos: 1, Verb: "f", Precision: 2},
+		} {
+			So(errors.Is(v.Validate(), ErrInvalidVariable), ShouldBeTrue)
+		}
+	})
 }
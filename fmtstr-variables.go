@@ -51,6 +51,45 @@ func (v Variables) Count() (argc int) {
 	return
 }
 
+// Lookup returns the first Variable with the given argument position, or
+// nil if not found
+func (v Variables) Lookup(pos int) (variable *Variable) {
+	for _, variable = range v {
+		if variable.Pos == pos {
+			return
+		}
+	}
+	variable = nil
+	return
+}
+
+// Compatible returns an error if any of the other Variables use argument
+// positions not present in these Variables or have conflicting substitution
+// types
+func (v Variables) Compatible(other Variables) (err error) {
+	for _, variable := range other {
+		if found := v.Lookup(variable.Pos); found == nil {
+			err = fmt.Errorf(`unknown argument: %v`, variable)
+			return
+		} else if !found.Verb.Equal(variable.Verb) {
+			err = fmt.Errorf(`conflicting substitution types: %v != %v`, found, variable)
+			return
+		}
+	}
+	return
+}
+
+// Missing returns the list of these Variables with argument positions that
+// are not present in the other Variables
+func (v Variables) Missing(other Variables) (missing Variables) {
+	for _, variable := range v {
+		if other.Lookup(variable.Pos) == nil {
+			missing = append(missing, variable)
+		}
+	}
+	return
+}
+
 func (v Variables) updateLabels() {
 	// check all variables for uniqueness
 	// duplicates get numeric suffix, other than the first
@@ -77,28 +116,36 @@ func (v Variables) updateLabels() {
 }
 
 func (v Variables) process(format string, argv []string) (replaced, labelled string, variables Variables, err error) {
+	if variables, err = v.resolve(); err != nil {
+		return
+	}
+
 	replaced = format[:]
 	labelled = format[:]
+	for _, variable := range v {
+		replaced = strings.Replace(replaced, variable.Source, variable.String(), 1)
+		labelled = strings.Replace(labelled, variable.Source, "{"+variable.Label+"}", 1)
+	}
+	return
+}
 
+// resolve updates the labels of all variables, checks for conflicting
+// substitution types and returns the sorted list of unique variables
+func (v Variables) resolve() (variables Variables, err error) {
 	v.updateLabels()
 
 	unique := map[int]*Variable{}
 	for _, variable := range v {
-
 		if orig, present := unique[variable.Pos]; present {
 			if !orig.Verb.Equal(variable.Verb) {
-				if err = fmt.Errorf(`conflicting substitution types: %v != %v`, orig, variable); err != nil {
-					replaced, labelled, variables = "", "", nil
-					return
-				}
+				err = fmt.Errorf(`conflicting substitution types: %v != %v`, orig, variable)
+				variables = nil
+				return
 			}
 		} else {
 			unique[variable.Pos] = variable
 			variables = append(variables, variable)
 		}
-
-		replaced = strings.Replace(replaced, variable.Source, variable.String(), 1)
-		labelled = strings.Replace(labelled, variable.Source, "{"+variable.Label+"}", 1)
 	}
 
 	variables = variables.Sort()
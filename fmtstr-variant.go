@@ -0,0 +1,137 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrInvalidVariant = errors.New("invalid variant")
+)
+
+// VariantOther is the required fallback case of a Variant
+const VariantOther = "other"
+
+// Variant is a message with a separate fmt format string for each of a set
+// of keywords, where the case is selected by the value of the text Variable
+// at the argument position Pos. Variant is the equivalent of the ICU select
+// argument and is useful for grammatical gender, formality and other such
+// message variations
+type Variant struct {
+	// Pos is the argument position of the text Variable which selects the
+	// variant case
+	Pos int
+	// Cases are the format strings for each of the keywords, the
+	// VariantOther case is required
+	Cases map[string]string
+	// Argv is the optional list of argument names, see Decompose
+	Argv []string
+}
+
+// NewVariant constructs a new Variant instance and returns any Validate
+// error
+func NewVariant(pos int, cases map[string]string, argv ...string) (variant *Variant, err error) {
+	variant = &Variant{
+		Pos:   pos,
+		Cases: cases,
+		Argv:  argv,
+	}
+	if err = variant.Validate(); err != nil {
+		variant = nil
+	}
+	return
+}
+
+// Validate checks that the VariantOther case is present, that all cases are
+// valid format strings (using Decompose) and that all cases are compatible
+// with the VariantOther case (see Variables.Compatible). The Variable at Pos
+// must be either text or any typed (ie: `%s` or `%v`)
+func (v *Variant) Validate() (err error) {
+	var other Variables
+	if other, err = v.Variables(); err != nil {
+		return
+	}
+	if found := other.Lookup(v.Pos); found == nil {
+		err = fmt.Errorf("%w: %s case does not use argument %d", ErrInvalidVariant, VariantOther, v.Pos)
+		return
+	} else if found.Type != "text" && found.Type != "any" {
+		err = fmt.Errorf("%w: argument %d is not text: %v", ErrInvalidVariant, v.Pos, found)
+		return
+	}
+
+	for _, key := range v.Keys() {
+		if key != VariantOther {
+			var variables Variables
+			if _, _, variables, err = Decompose(v.Cases[key], v.Argv...); err == nil {
+				err = other.Compatible(variables)
+			}
+			if err != nil {
+				err = fmt.Errorf("%w: %s case: %w", ErrInvalidVariant, key, err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// Keys returns the sorted list of case keywords
+func (v *Variant) Keys() (keys []string) {
+	for key := range v.Cases {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// Variables returns the Decompose Variables of the VariantOther case
+func (v *Variant) Variables() (variables Variables, err error) {
+	if format, present := v.Cases[VariantOther]; !present {
+		err = fmt.Errorf("%w: missing %s case", ErrInvalidVariant, VariantOther)
+	} else if _, _, variables, err = Decompose(format, v.Argv...); err != nil {
+		err = fmt.Errorf("%w: %s case: %w", ErrInvalidVariant, VariantOther, err)
+	}
+	return
+}
+
+// Select returns the format string of the case for the given keyword,
+// falling back to the VariantOther case
+func (v *Variant) Select(keyword string) (format string) {
+	if found, present := v.Cases[keyword]; present {
+		return found
+	}
+	format = v.Cases[VariantOther]
+	return
+}
+
+// Sprintf selects the case using the argument at Pos and returns the result
+// of formatting the case with the arguments given. The argument is converted
+// to the case keyword with fmt.Sprint and the case is formatted with
+// Segments.Sprintf so that cases which do not use all of the arguments are
+// formatted correctly
+func (v *Variant) Sprintf(argv ...interface{}) (result string, err error) {
+	if v.Pos < 1 || v.Pos > len(argv) {
+		err = fmt.Errorf("%w: missing argument %d", ErrInvalidVariant, v.Pos)
+		return
+	}
+	var segments Segments
+	if segments, _, err = Parse(v.Select(fmt.Sprint(argv[v.Pos-1])), v.Argv...); err != nil {
+		return
+	}
+	result = segments.Sprintf(argv...)
+	return
+}
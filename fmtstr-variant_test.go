@@ -0,0 +1,87 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestVariant(t *testing.T) {
+	Convey("NewVariant", t, func() {
+		variant, err := NewVariant(1, map[string]string{
+			"female":     "%[2]s invited her friends",
+			"male":       "%[2]s invited his friends",
+			VariantOther: "%[2]s invited their friends (%[1]s)",
+		}, ".Gender", ".Name")
+		So(err, ShouldEqual, nil)
+		So(variant, ShouldNotBeNil)
+		So(variant.Keys(), ShouldResemble, []string{"female", "male", "other"})
+
+		So(variant.Select("male"), ShouldEqual, "%[2]s invited his friends")
+		So(variant.Select("unknown"), ShouldEqual, "%[2]s invited their friends (%[1]s)")
+
+		result, err := variant.Sprintf("female", "Alice")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "Alice invited her friends")
+		result, err = variant.Sprintf("none", "Sam")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "Sam invited their friends (none)")
+		result, err = variant.Sprintf()
+		So(errors.Is(err, ErrInvalidVariant), ShouldBeTrue)
+		So(result, ShouldEqual, "")
+
+		// cases without directives ignore the unused arguments
+		pronoun, err := NewVariant(1, map[string]string{
+			"female":     "She",
+			"male":       "He",
+			VariantOther: "%[1]s",
+		}, ".Gender")
+		So(err, ShouldEqual, nil)
+		result, err = pronoun.Sprintf("female")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "She")
+		result, err = pronoun.Sprintf("They")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldEqual, "They")
+
+		variables, err := variant.Variables()
+		So(err, ShouldEqual, nil)
+		So(len(variables), ShouldEqual, 2)
+		So(variables[0].Label, ShouldEqual, "Gender")
+
+		variant, err = NewVariant(1, map[string]string{"formal": "%v"})
+		So(errors.Is(err, ErrInvalidVariant), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "invalid variant: missing other case")
+		So(variant, ShouldBeNil)
+
+		_, err = NewVariant(2, map[string]string{VariantOther: "%v"})
+		So(err.Error(), ShouldEqual, "invalid variant: other case does not use argument 2")
+
+		_, err = NewVariant(1, map[string]string{VariantOther: "%d"})
+		So(err.Error(), ShouldEqual, "invalid variant: argument 1 is not text: %[1]d")
+
+		_, err = NewVariant(1, map[string]string{"formal": "%v %d", VariantOther: "%v"})
+		So(err.Error(), ShouldEqual, "invalid variant: formal case: unknown argument: %[2]d")
+
+		_, err = NewVariant(1, map[string]string{"formal": "%s %[2]d", VariantOther: "%s %s"})
+		So(err.Error(), ShouldEqual, "invalid variant: formal case: conflicting substitution types: %[2]s != %[2]d")
+
+		_, err = NewVariant(1, map[string]string{"formal": "%!", VariantOther: "%s"})
+		So(err.Error(), ShouldEqual, "invalid variant: formal case: invalid format at: %!")
+	})
+}
@@ -15,15 +15,26 @@
 package fmtstr
 
 import (
+	"strings"
+
 	"github.com/iancoleman/strcase"
 )
 
+// GoVerbs is the list of all verbs supported by the fmt package
+const GoVerbs = "bcdeEfFgGoOpqstTUvwxX"
+
 type Verb string
 
 func (v Verb) String() string {
 	return string(v)
 }
 
+// IsGo returns true if this Verb is one of the GoVerbs
+func (v Verb) IsGo() (valid bool) {
+	valid = len(v) == 1 && strings.Contains(GoVerbs, string(v))
+	return
+}
+
 func (v Verb) Label() string {
 	if v == "-" || v == "q" || v == "v" {
 		return "Var"
@@ -41,11 +52,46 @@ func (v Verb) Type() string {
 		return "text"
 	case "t":
 		return "bool"
+	case "w":
+		return "error"
 	case "q", "T", "v":
 	}
 	return "any"
 }
 
+// Canonical returns the preferred form of verbs which fmt treats as exact
+// synonyms, for example: `F` is a synonym for `f`
+func (v Verb) Canonical() (canonical Verb) {
+	switch v {
+	case "F":
+		canonical = "f"
+	default:
+		canonical = v
+	}
+	return
+}
+
+// GoType returns the name of the Go type typically used with this Verb,
+// derived from the Verb.Type: "int" for num, "float64" for float, "string"
+// for text, "bool" for bool, "error" for error and "interface{}" for any
+func (v Verb) GoType() (name string) {
+	switch v.Type() {
+	case "num":
+		name = "int"
+	case "float":
+		name = "float64"
+	case "text":
+		name = "string"
+	case "bool":
+		name = "bool"
+	case "error":
+		name = "error"
+	default:
+		name = "interface{}"
+	}
+	return
+}
+
 func (v Verb) Equal(o Verb) (equal bool) {
 	self := v.Type()
 	other := o.Type()
@@ -0,0 +1,344 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidXLIFF = errors.New("invalid XLIFF data")
+)
+
+const (
+	XLIFFVersion12 = "1.2"
+	XLIFFVersion20 = "2.0"
+)
+
+// XLIFFFile is an XLIFF 1.2 or 2.0 document with a single file of
+// translation units
+type XLIFFFile struct {
+	Version        string
+	SourceLanguage string
+	TargetLanguage string
+	Units          []*XLIFFUnit
+}
+
+// XLIFFUnit is a single XLIFF translation unit, where the Source is the
+// original Message and the Target is the fmt format string translation
+type XLIFFUnit struct {
+	ID     string
+	Source *Message
+	Target string
+}
+
+// NewXLIFFFile constructs a new XLIFFFile with an XLIFFUnit for each of the
+// source Messages given
+func NewXLIFFFile(version, sourceLanguage, targetLanguage string, sources ...*Message) (file *XLIFFFile) {
+	file = &XLIFFFile{
+		Version:        version,
+		SourceLanguage: sourceLanguage,
+		TargetLanguage: targetLanguage,
+	}
+	for _, source := range sources {
+		file.Units = append(file.Units, NewXLIFFUnit(source))
+	}
+	return
+}
+
+// NewXLIFFUnit constructs a new XLIFFUnit for the given source Message, the
+// ID is derived from the source Message Format so that the same ID is
+// produced every time
+func NewXLIFFUnit(source *Message) (unit *XLIFFUnit) {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(source.Format))
+	unit = &XLIFFUnit{
+		ID:     "m" + strconv.FormatUint(hash.Sum64(), 16),
+		Source: source,
+	}
+	return
+}
+
+// Write writes the XLIFF document to the given writer. Each of the source
+// and target substitution Variables are written as XLIFF 2.0 `<ph>` or
+// XLIFF 1.2 `<x>` elements, with their `id` numbered in the order of the
+// source Variables (see XLIFFUnit.inline for repeated Variables), their
+// `equiv` (or `equiv-text`) set to the curly-braced Label and the XLIFF 2.0
+// `disp` set to the Variable's fmt directive
+func (f *XLIFFFile) Write(w io.Writer) (err error) {
+	var buf strings.Builder
+	buf.WriteString(xml.Header)
+
+	if f.Version == XLIFFVersion12 {
+		buf.WriteString(`<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">` + "\n")
+		buf.WriteString(`  <file original="fmtstr" datatype="plaintext" source-language="` + xmlEscape(f.SourceLanguage) + `"`)
+		if f.TargetLanguage != "" {
+			buf.WriteString(` target-language="` + xmlEscape(f.TargetLanguage) + `"`)
+		}
+		buf.WriteString(">\n    <body>\n")
+	} else if f.Version == XLIFFVersion20 {
+		buf.WriteString(`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="` + xmlEscape(f.SourceLanguage) + `"`)
+		if f.TargetLanguage != "" {
+			buf.WriteString(` trgLang="` + xmlEscape(f.TargetLanguage) + `"`)
+		}
+		buf.WriteString(">\n  <file id=\"f1\">\n")
+	} else {
+		err = fmt.Errorf("%w: unsupported version %q", ErrInvalidXLIFF, f.Version)
+		return
+	}
+
+	for _, unit := range f.Units {
+		var source, target string
+		if source, target, err = unit.inline(f.Version); err != nil {
+			return
+		}
+		if f.Version == XLIFFVersion12 {
+			buf.WriteString(`      <trans-unit id="` + xmlEscape(unit.ID) + `">` + "\n")
+			buf.WriteString("        <source>" + source + "</source>\n")
+			if unit.Target != "" {
+				buf.WriteString("        <target>" + target + "</target>\n")
+			}
+			buf.WriteString("      </trans-unit>\n")
+			continue
+		}
+		buf.WriteString(`    <unit id="` + xmlEscape(unit.ID) + `">` + "\n")
+		buf.WriteString("      <segment>\n")
+		buf.WriteString("        <source>" + source + "</source>\n")
+		if unit.Target != "" {
+			buf.WriteString("        <target>" + target + "</target>\n")
+		}
+		buf.WriteString("      </segment>\n")
+		buf.WriteString("    </unit>\n")
+	}
+
+	if f.Version == XLIFFVersion12 {
+		buf.WriteString("    </body>\n  </file>\n</xliff>\n")
+	} else {
+		buf.WriteString("  </file>\n</xliff>\n")
+	}
+	_, err = io.WriteString(w, buf.String())
+	return
+}
+
+// placeholders returns the parsed source Segments and the source Variable
+// occurrences, in order
+func (u *XLIFFUnit) placeholders() (segments Segments, variables Variables, err error) {
+	if segments, _, err = Parse(u.Source.Format, u.Source.Argv...); err == nil {
+		variables = segments.Variables()
+	}
+	return
+}
+
+// inline returns the XML content of the source and target elements. Each of
+// the source Variable occurrences has its own id and each of the target
+// Variables uses the id of the next unused source occurrence of the same
+// argument position. When a target uses an argument more times than the
+// source, the XLIFF 2.0 repeats have new ids with `copyOf` set to the first
+// source id, as ids must be unique within the unit (XLIFF 1.2 has no
+// equivalent and repeats the first source id)
+func (u *XLIFFUnit) inline(version string) (source, target string, err error) {
+	var segments Segments
+	var placeholders Variables
+	if segments, placeholders, err = u.placeholders(); err != nil {
+		return
+	}
+
+	element := func(id, copyOf int, v *Variable) string {
+		if version == XLIFFVersion12 {
+			return `<x id="` + strconv.Itoa(id) + `" equiv-text="` + xmlEscape("{"+v.Label+"}") + `"/>`
+		}
+		value := `<ph id="` + strconv.Itoa(id) + `"`
+		if copyOf > 0 {
+			value += ` copyOf="` + strconv.Itoa(copyOf) + `"`
+		}
+		return value + ` equiv="` + xmlEscape("{"+v.Label+"}") + `" disp="` + xmlEscape(v.directive(false)) + `"/>`
+	}
+
+	var id int
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			source += xmlEscape(segment.Text)
+			continue
+		}
+		id += 1
+		source += element(id, 0, placeholders[id-1])
+	}
+
+	if u.Target == "" {
+		return
+	} else if _, err = u.Source.Check(u.Target); err != nil {
+		return
+	} else if segments, _, err = Parse(u.Target, u.Source.Argv...); err != nil {
+		return
+	}
+
+	used := make(map[int]struct{})
+	for _, segment := range segments {
+		if segment.Variable == nil {
+			target += xmlEscape(segment.Text)
+			continue
+		}
+		var found, first int
+		for idx, placeholder := range placeholders {
+			if placeholder.Pos == segment.Variable.Pos {
+				if first == 0 {
+					first = idx + 1
+				}
+				if _, present := used[idx+1]; !present {
+					found = idx + 1
+					break
+				}
+			}
+		}
+		if found > 0 {
+			used[found] = struct{}{}
+			target += element(found, 0, placeholders[found-1])
+		} else if version == XLIFFVersion12 {
+			target += element(first, 0, placeholders[first-1])
+		} else {
+			id += 1
+			target += element(id, first, placeholders[first-1])
+		}
+	}
+	return
+}
+
+// ReadXLIFFFile parses the XLIFF 1.2 or 2.0 document from the given reader.
+// Each of the translation units must have the ID of one of the given source
+// Messages (see NewXLIFFUnit) and the target of each unit is reconstructed
+// into a fmt format string by replacing the `<ph>` or `<x>` elements with
+// the original Variables of the source Message, and is then checked with
+// Message.Check
+func ReadXLIFFFile(r io.Reader, sources ...*Message) (file *XLIFFFile, err error) {
+	lookup := make(map[string]*Message)
+	for _, source := range sources {
+		lookup[NewXLIFFUnit(source).ID] = source
+	}
+
+	file = &XLIFFFile{}
+	var unit *XLIFFUnit
+	var segments Segments
+	var placeholders Variables
+	var inTarget bool
+
+	decoder := xml.NewDecoder(r)
+	for {
+		var token xml.Token
+		if token, err = decoder.Token(); err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidXLIFF, err)
+			break
+		}
+
+		switch t := token.(type) {
+
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "xliff":
+				file.Version = xmlAttr(t, "version")
+				file.SourceLanguage = xmlAttr(t, "srcLang")
+				file.TargetLanguage = xmlAttr(t, "trgLang")
+			case "file":
+				if file.Version == XLIFFVersion12 {
+					file.SourceLanguage = xmlAttr(t, "source-language")
+					file.TargetLanguage = xmlAttr(t, "target-language")
+				}
+			case "unit", "trans-unit":
+				id := xmlAttr(t, "id")
+				if source, present := lookup[id]; !present {
+					err = fmt.Errorf("%w: unknown unit %q", ErrInvalidXLIFF, id)
+				} else {
+					unit = &XLIFFUnit{ID: id, Source: source}
+					_, placeholders, err = unit.placeholders()
+				}
+			case "target":
+				inTarget, segments = unit != nil, nil
+			case "ph", "x":
+				if inTarget {
+					id, _ := strconv.Atoi(xmlAttr(t, "id"))
+					if copyOf := xmlAttr(t, "copyOf"); copyOf != "" {
+						// a repeated placeholder, see XLIFFUnit.inline
+						id, _ = strconv.Atoi(copyOf)
+					}
+					if id < 1 || id > len(placeholders) {
+						err = fmt.Errorf("%w: unit %q has unknown placeholder %q", ErrInvalidXLIFF, unit.ID, xmlAttr(t, "id"))
+					} else {
+						segments = append(segments, &Segment{Variable: placeholders[id-1]})
+					}
+				}
+			}
+
+		case xml.CharData:
+			if inTarget {
+				if last := len(segments) - 1; last >= 0 && segments[last].Variable == nil {
+					segments[last].Text += string(t)
+				} else {
+					segments = append(segments, &Segment{Text: string(t)})
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "target":
+				if inTarget {
+					inTarget = false
+					unit.Target = segments.String()
+					if _, err = unit.Source.Check(unit.Target); err != nil {
+						err = fmt.Errorf("%w: unit %q: %w", ErrInvalidXLIFF, unit.ID, err)
+					}
+				}
+			case "unit", "trans-unit":
+				if unit != nil {
+					file.Units = append(file.Units, unit)
+					unit = nil
+				}
+			}
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		file = nil
+	}
+	return
+}
+
+func xmlAttr(element xml.StartElement, name string) (value string) {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			value = attr.Value
+			return
+		}
+	}
+	return
+}
+
+func xmlEscape(text string) (escaped string) {
+	var buf strings.Builder
+	_ = xml.EscapeText(&buf, []byte(text))
+	escaped = buf.String()
+	return
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmtstr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestXLIFF(t *testing.T) {
+	source, _ := NewMessage("%s has %d <items> (100%%)", ".Name", ".Count")
+	unit := NewXLIFFUnit(source)
+
+	Convey("XLIFF 2.0", t, func() {
+		file := NewXLIFFFile(XLIFFVersion20, "en", "de", source)
+		file.Units[0].Target = "%[2]d <Artikel> gehören %[1]s (100%%)"
+
+		var buf bytes.Buffer
+		So(file.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="de">
+  <file id="f1">
+    <unit id="`+unit.ID+`">
+      <segment>
+        <source><ph id="1" equiv="{Name}" disp="%s"/> has <ph id="2" equiv="{Count}" disp="%d"/> &lt;items&gt; (100%)</source>
+        <target><ph id="2" equiv="{Count}" disp="%d"/> &lt;Artikel&gt; gehören <ph id="1" equiv="{Name}" disp="%s"/> (100%)</target>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`)
+
+		read, err := ReadXLIFFFile(&buf, source)
+		So(err, ShouldEqual, nil)
+		So(read.Version, ShouldEqual, XLIFFVersion20)
+		So(read.SourceLanguage, ShouldEqual, "en")
+		So(read.TargetLanguage, ShouldEqual, "de")
+		So(len(read.Units), ShouldEqual, 1)
+		So(read.Units[0].Source, ShouldEqual, source)
+		So(read.Units[0].Target, ShouldEqual, "%[2]d <Artikel> gehören %[1]s (100%%)")
+	})
+
+	Convey("XLIFF 2.0 repeated placeholders", t, func() {
+		repeated, _ := NewMessage("%[1]s and %[1]s have %[2]d", ".Name", ".Count")
+		file := NewXLIFFFile(XLIFFVersion20, "en", "de", repeated)
+		file.Units[0].Target = "%[2]d: %[1]s, %[1]s, %[1]s, %[2]d"
+
+		var buf bytes.Buffer
+		So(file.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldContainSubstring, `<source><ph id="1" equiv="{Name}" disp="%s"/> and <ph id="2" equiv="{Name}" disp="%s"/> have <ph id="3" equiv="{Count}" disp="%d"/></source>`)
+		So(buf.String(), ShouldContainSubstring, `<target><ph id="3" equiv="{Count}" disp="%d"/>: <ph id="1" equiv="{Name}" disp="%s"/>, <ph id="2" equiv="{Name}" disp="%s"/>, <ph id="4" copyOf="1" equiv="{Name}" disp="%s"/>, <ph id="5" copyOf="3" equiv="{Count}" disp="%d"/></target>`)
+
+		read, err := ReadXLIFFFile(&buf, repeated)
+		So(err, ShouldEqual, nil)
+		So(read.Units[0].Target, ShouldEqual, "%[2]d: %[1]s, %[1]s, %[1]s, %d")
+	})
+
+	Convey("XLIFF 1.2", t, func() {
+		file := NewXLIFFFile(XLIFFVersion12, "en", "", source)
+
+		var buf bytes.Buffer
+		So(file.Write(&buf), ShouldEqual, nil)
+		So(buf.String(), ShouldEqual, `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">
+  <file original="fmtstr" datatype="plaintext" source-language="en">
+    <body>
+      <trans-unit id="`+unit.ID+`">
+        <source><x id="1" equiv-text="{Name}"/> has <x id="2" equiv-text="{Count}"/> &lt;items&gt; (100%)</source>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`)
+
+		translated := strings.Replace(buf.String(), "</source>", `</source>
+        <target><x id="1"/> a <x id="2"/> &lt;articles&gt;</target>`, 1)
+		translated = strings.Replace(translated, `source-language="en"`, `source-language="en" target-language="fr"`, 1)
+		read, err := ReadXLIFFFile(strings.NewReader(translated), source)
+		So(err, ShouldEqual, nil)
+		So(read.Version, ShouldEqual, XLIFFVersion12)
+		So(read.TargetLanguage, ShouldEqual, "fr")
+		So(read.Units[0].Target, ShouldEqual, "%s a %d <articles>")
+	})
+
+	Convey("Errors", t, func() {
+		var buf bytes.Buffer
+		err := NewXLIFFFile("3.0", "en", "").Write(&buf)
+		So(errors.Is(err, ErrInvalidXLIFF), ShouldBeTrue)
+
+		file := NewXLIFFFile(XLIFFVersion20, "en", "de", source)
+		file.Units[0].Target = "%d"
+		So(errors.Is(file.Write(&buf), ErrInvalidTranslation), ShouldBeTrue)
+
+		_, err = ReadXLIFFFile(strings.NewReader(`<xliff version="2.0"><file><unit id="nope"></unit></file></xliff>`), source)
+		So(err.Error(), ShouldEqual, `invalid XLIFF data: unknown unit "nope"`)
+
+		_, err = ReadXLIFFFile(strings.NewReader(`<xliff version="2.0"><file><unit id="`+unit.ID+`"><segment><target><ph id="3"/></target></segment></unit></file></xliff>`), source)
+		So(err.Error(), ShouldEqual, `invalid XLIFF data: unit "`+unit.ID+`" has unknown placeholder "3"`)
+
+		_, err = ReadXLIFFFile(strings.NewReader(`<xliff version="2.0"><file><unit id="`+unit.ID+`"><segment><target><ph id="1"/></target></segment></unit></file></xliff>`), source)
+		So(errors.Is(err, ErrInvalidTranslation), ShouldBeTrue)
+
+		_, err = ReadXLIFFFile(strings.NewReader(`<xliff`), source)
+		So(errors.Is(err, ErrInvalidXLIFF), ShouldBeTrue)
+	})
+}
@@ -65,13 +65,37 @@ var (
 // derive a meaningful label from that. For example: `%d` would become
 // `Num1` and `%f` would become `Float1`.
 func Decompose(format string, argv ...string) (replaced, labelled string, variables Variables, err error) {
+	var segments Segments
+	if segments, err = parse(format, argv); err != nil {
+		return
+	}
+	replaced, labelled, variables, err = segments.Variables().process(format, argv)
+	return
+}
+
+// Parse is the same as Decompose except that instead of the replaced and
+// labelled format strings, Parse returns the list of literal text and
+// substitution Variable Segments that make up the given format string.
+// The Variables returned are the same as Decompose and the Segments share
+// the same Variable instances
+func Parse(format string, argv ...string) (segments Segments, variables Variables, err error) {
+	if segments, err = parse(format, argv); err != nil {
+		return
+	}
+	if variables, err = segments.Variables().resolve(); err != nil {
+		segments = nil
+	}
+	return
+}
+
+func parse(format string, argv []string) (segments Segments, err error) {
 
 	var opened, closed bool
 	var position string
-	var list Variables
 	var state *cState
 
-	currentPos := 1 // currentPos is the positional parameter index, not a string index
+	currentPos := 1        // currentPos is the positional parameter index, not a string index
+	start, literal := 0, 0 // start of the current variable and literal text
 	last := len(format) - 1
 
 	for i := 0; i <= last; i++ {
@@ -82,6 +106,10 @@ func Decompose(format string, argv ...string) (replaced, labelled string, variab
 		if state, ok, err = checkContinue(currentPos, r, state); err != nil {
 			return
 		} else if !ok {
+			if state != nil {
+				// found the start of a new variable
+				start = i
+			}
 			continue
 		}
 
@@ -105,13 +133,17 @@ func Decompose(format string, argv ...string) (replaced, labelled string, variab
 		}
 
 		switch r {
-		case 'b', 'c', 'd', 'e', 'E', 'f', 'F', 'g', 'G', 'o', 'O', 'p', 'q', 's', 't', 'T', 'U', 'v', 'x', 'X':
+		case 'b', 'c', 'd', 'e', 'E', 'f', 'F', 'g', 'G', 'o', 'O', 'p', 'q', 's', 't', 'T', 'U', 'v', 'w', 'x', 'X':
 			// found a valid variable type which concludes this substitution
 			// variable parameter
 
 			state.verb = Verb(char)
 
-			list = append(list, state.make(argv))
+			if start > literal {
+				segments = append(segments, newTextSegment(format[literal:start]))
+			}
+			segments = append(segments, &Segment{Variable: state.make(argv)})
+			literal = i + 1
 
 			state = nil
 			opened = false
@@ -140,6 +172,11 @@ func Decompose(format string, argv ...string) (replaced, labelled string, variab
 		case ']':
 			closed = true
 			v, _ := strconv.Atoi(position)
+			if v < 1 {
+				// fmt argument indexes start at one
+				err = fmt.Errorf("invalid format at: %v", state.source)
+				return
+			}
 			state.pos = v
 			currentPos = v
 
@@ -161,7 +198,9 @@ func Decompose(format string, argv ...string) (replaced, labelled string, variab
 
 	}
 
-	replaced, labelled, variables, err = list.process(format, argv)
+	if literal <= last {
+		segments = append(segments, newTextSegment(format[literal:]))
+	}
 	return
 }
 
@@ -146,5 +146,43 @@ func Test(t *testing.T) {
 		So(labelled, ShouldEqual, "")
 		So(len(variables), ShouldEqual, 0)
 
+		_, _, _, err = Decompose("One var %[0]d")
+		So(err, ShouldNotEqual, nil)
+		So(err.Error(), ShouldEqual, "invalid format at: %[0]")
+
+	})
+
+	Convey("Parse", t, func() {
+
+		segments, variables, err := Parse("")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 0)
+		So(len(variables), ShouldEqual, 0)
+
+		segments, variables, err = Parse("100%% of %[2]s and %d %[2]s!", ".Num", ".Name")
+		So(err, ShouldEqual, nil)
+		So(len(segments), ShouldEqual, 7)
+		So(segments[0].Text, ShouldEqual, "100% of ")
+		So(segments[1].IsVariable(), ShouldBeTrue)
+		So(segments[1].Variable.Label, ShouldEqual, "Name")
+		So(segments[6].Text, ShouldEqual, "!")
+		So(len(variables), ShouldEqual, 2)
+		So(len(segments.Variables()), ShouldEqual, 3)
+		So(segments.String(), ShouldEqual, "100%% of %[2]s and %d %[2]s!")
+		So(segments.Replaced(), ShouldEqual, "100%% of %[2]s and %[3]d %[2]s!")
+		So(segments.Labelled(), ShouldEqual, "100%% of {Name} and {Num} {Name}!")
+
+		segments, variables, err = Parse("read %s: %w", ".Path", ".Err")
+		So(err, ShouldEqual, nil)
+		So(variables[1].Verb, ShouldEqual, Verb("w"))
+		So(variables[1].Type, ShouldEqual, "error")
+		So(variables[1].Verb.GoType(), ShouldEqual, "error")
+		So(segments.Labelled(), ShouldEqual, "read {Path}: {Err}")
+
+		segments, variables, err = Parse("%d %[1]s")
+		So(err, ShouldNotEqual, nil)
+		So(len(segments), ShouldEqual, 0)
+		So(len(variables), ShouldEqual, 0)
+
 	})
 }
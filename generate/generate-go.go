@@ -0,0 +1,223 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+// DefaultGoPackage is the package name used when GoGenerator.Package is empty
+const DefaultGoPackage = "messages"
+
+// gGoPrinterParam is the name of the generated *message.Printer parameter
+const gGoPrinterParam = "p"
+
+// GoGenerator generates Go source code with a function for each Entry, ie:
+//
+//	func ItemsInCart(p *message.Printer, count int, user string) string {
+//		return p.Sprintf("%d items in %s's cart", count, user)
+//	}
+//
+// The parameter types are the Verb.GoType of the Variables, except for the
+// verbs accepting several kinds of values (%p, %x and %X) which take an
+// interface{}, and the source Message Format is passed unchanged to the
+// golang.org/x/text/message Printer, so that it is the catalog key of the
+// translations. GenerateTest returns a test file for the generated code
+type GoGenerator struct {
+	// Package is the name of the generated Go package
+	Package string
+}
+
+// NewGoGenerator returns a new GoGenerator for the given package name
+func NewGoGenerator(pkg string) (g *GoGenerator) {
+	g = &GoGenerator{Package: pkg}
+	return
+}
+
+// Generate returns the gofmt'd Go source code for the given entries, the
+// entries must have unique exported names and use all of their argument
+// positions
+func (g *GoGenerator) Generate(entries ...*Entry) (source []byte, err error) {
+	var pkg string
+	if pkg, err = g.prepare(entries); err != nil {
+		return
+	}
+
+	var buf strings.Builder
+	buf.WriteString("// Code generated by fmtstr; DO NOT EDIT.\n\n")
+	buf.WriteString("package " + pkg + "\n\n")
+	buf.WriteString("import \"golang.org/x/text/message\"\n")
+
+	for _, entry := range entries {
+		var params []*cParam
+		if params, err = entry.params(goReserved()...); err != nil {
+			return
+		}
+
+		buf.WriteString("\n// " + entry.Name + " returns the translation of:\n//\n//\t" + goComment(entry.Message.Labelled) + "\n")
+		if entry.Comment != "" {
+			buf.WriteString("//\n")
+			for _, line := range strings.Split(entry.Comment, "\n") {
+				buf.WriteString(strings.TrimRight("// "+goComment(line), " ") + "\n")
+			}
+		}
+
+		signature := []string{gGoPrinterParam + " *message.Printer"}
+		call := []string{strconv.Quote(entry.Message.Format)}
+		for _, param := range params {
+			signature = append(signature, param.name+" "+goType(param.variable))
+			call = append(call, param.name)
+		}
+		buf.WriteString("func " + entry.Name + "(" + strings.Join(signature, ", ") + ") string {\n")
+		buf.WriteString("\treturn " + gGoPrinterParam + ".Sprintf(" + strings.Join(call, ", ") + ")\n")
+		buf.WriteString("}\n")
+	}
+
+	source, err = format.Source([]byte(buf.String()))
+	return
+}
+
+// GenerateTest returns the gofmt'd Go test source code for the functions
+// that Generate returns for the given entries. Each test calls the function
+// with sample arguments and an English message.Printer and compares the
+// result with fmt.Sprintf of the source Message Format
+func (g *GoGenerator) GenerateTest(entries ...*Entry) (source []byte, err error) {
+	var pkg string
+	if pkg, err = g.prepare(entries); err != nil {
+		return
+	}
+
+	var buf strings.Builder
+	buf.WriteString("// Code generated by fmtstr; DO NOT EDIT.\n\n")
+	buf.WriteString("package " + pkg + "\n")
+	if len(entries) > 0 {
+		buf.WriteString("\nimport (\n\t\"fmt\"\n\t\"testing\"\n\n")
+		buf.WriteString("\t\"golang.org/x/text/language\"\n\t\"golang.org/x/text/message\"\n)\n")
+	}
+
+	for _, entry := range entries {
+		var params []*cParam
+		if params, err = entry.params(goReserved(gGoTestLocals...)...); err != nil {
+			return
+		}
+
+		var names, samples []string
+		for _, param := range params {
+			names = append(names, param.name)
+			samples = append(samples, goSample(param.variable))
+		}
+		args := strings.Join(append([]string{strconv.Quote(entry.Message.Format)}, names...), ", ")
+
+		buf.WriteString("\nfunc Test" + entry.Name + "(t *testing.T) {\n")
+		buf.WriteString("\t" + gGoPrinterParam + " := message.NewPrinter(language.English)\n")
+		if len(params) > 0 {
+			buf.WriteString("\t" + strings.Join(names, ", ") + " := " + strings.Join(samples, ", ") + "\n")
+		}
+		buf.WriteString("\tgot := " + entry.Name + "(" + strings.Join(append([]string{gGoPrinterParam}, names...), ", ") + ")\n")
+		buf.WriteString("\tif want := fmt.Sprintf(" + args + "); got != want {\n")
+		buf.WriteString("\t\tt.Errorf(\"" + entry.Name + "() = %q, want %q\", got, want)\n")
+		buf.WriteString("\t}\n}\n")
+	}
+
+	source, err = format.Source([]byte(buf.String()))
+	return
+}
+
+// prepare returns the package name of the generated code and validates the
+// entries given, which must not use the %w verb as it is only supported by
+// fmt.Errorf
+func (g *GoGenerator) prepare(entries []*Entry) (pkg string, err error) {
+	if pkg = g.Package; pkg == "" {
+		pkg = DefaultGoPackage
+	}
+	if !token.IsIdentifier(pkg) {
+		err = fmt.Errorf("%w: %q is not a valid package name", ErrInvalidEntry, pkg)
+		return
+	} else if err = validate(entries); err != nil {
+		return
+	}
+	for _, entry := range entries {
+		for _, variable := range entry.Message.Variables {
+			if variable.Verb == "w" {
+				err = fmt.Errorf("%w: %s: %q is not supported by message.Printer", ErrInvalidEntry, entry.Name, variable.Source)
+				return
+			}
+		}
+	}
+	return
+}
+
+// gGoTestLocals are the identifiers used by the generated tests, in addition
+// to the printer parameter
+var gGoTestLocals = []string{"t", "got", "want", "fmt", "testing", "language", "message"}
+
+// goReserved returns the names which generated parameters must not use: the
+// printer parameter, the predeclared identifiers, the Go keywords and the
+// extra names given
+func goReserved(extra ...string) (reserved []string) {
+	reserved = append([]string{gGoPrinterParam}, types.Universe.Names()...)
+	for tok := token.BREAK; tok <= token.VAR; tok++ {
+		reserved = append(reserved, tok.String())
+	}
+	reserved = append(reserved, extra...)
+	return
+}
+
+// goType returns the Go parameter type for the Variable given, which is the
+// Verb.GoType except for %p, %x and %X which accept several kinds of values
+// and are typed as interface{}
+func goType(variable *fmtstr.Variable) (name string) {
+	switch variable.Verb {
+	case "p", "x", "X":
+		name = "interface{}"
+	default:
+		name = variable.Verb.GoType()
+	}
+	return
+}
+
+// goSample returns the Go expression of a sample argument for the Variable
+// given, used by GenerateTest: a pointer for %p and otherwise a value of
+// the goType, with the quoted Label as the sample text
+func goSample(variable *fmtstr.Variable) (expr string) {
+	if variable.Verb == "p" {
+		return "new(int)"
+	}
+	switch goType(variable) {
+	case "int":
+		expr = "42"
+	case "float64":
+		expr = "3.5"
+	case "bool":
+		expr = "true"
+	default:
+		expr = strconv.Quote(variable.Label)
+	}
+	return
+}
+
+// goComment returns the text with any line breaks escaped, for use within a
+// single line comment
+func goComment(text string) (comment string) {
+	comment = strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(text)
+	return
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+var gUpdate = flag.Bool("update", false, "update the generated golden files")
+
+// gTestEntries are the entries of the generated internal/messages package,
+// which has generated and hand-written tests calling each of the generated
+// functions
+var gTestEntries = []struct {
+	name    string
+	comment string
+	format  string
+	argv    []string
+}{
+	{"ItemsInCart", "shown on the cart page", "%[2]s has %[1]d items in the cart", []string{".Count", ".User"}},
+	{"", "", "%d items in %s's cart", []string{".Count", ".User"}},
+	{"order total", "", "Total: %8.2f (paid: %t)", []string{".Total", ".Paid"}},
+	{"Welcome", "greeting\nwith two lines", "Welcome back, %q!", []string{".Type"}},
+	{"NoArgs", "", "Nothing to see here", nil},
+	{"Mixed", "", "%v and %x and %s", []string{".P", ".String", ".String"}},
+	{"Stored", "", "%s stored at %p", []string{".Name", ".Addr"}},
+}
+
+func testEntries() (entries []*Entry) {
+	for _, test := range gTestEntries {
+		message, err := fmtstr.NewMessage(test.format, test.argv...)
+		So(err, ShouldEqual, nil)
+		entries = append(entries, NewEntry(test.name, test.comment, message))
+	}
+	return
+}
+
+func TestGoGenerator(t *testing.T) {
+	Convey("Generate", t, func() {
+		source, err := NewGoGenerator("messages").Generate(testEntries()...)
+		So(err, ShouldEqual, nil)
+
+		golden := "internal/messages/messages.go"
+		if *gUpdate {
+			So(os.WriteFile(golden, source, 0644), ShouldEqual, nil)
+		}
+		expected, err := os.ReadFile(golden)
+		So(err, ShouldEqual, nil)
+		So(string(source), ShouldEqual, string(expected))
+	})
+
+	Convey("GenerateTest", t, func() {
+		source, err := NewGoGenerator("messages").GenerateTest(testEntries()...)
+		So(err, ShouldEqual, nil)
+
+		golden := "internal/messages/messages_generated_test.go"
+		if *gUpdate {
+			So(os.WriteFile(golden, source, 0644), ShouldEqual, nil)
+		}
+		expected, err := os.ReadFile(golden)
+		So(err, ShouldEqual, nil)
+		So(string(source), ShouldEqual, string(expected))
+
+		source, err = (&GoGenerator{}).GenerateTest()
+		So(err, ShouldEqual, nil)
+		So(string(source), ShouldEqual, "// Code generated by fmtstr; DO NOT EDIT.\n\npackage messages\n")
+	})
+
+	Convey("default package", t, func() {
+		source, err := (&GoGenerator{}).Generate()
+		So(err, ShouldEqual, nil)
+		So(string(source), ShouldEqual, "// Code generated by fmtstr; DO NOT EDIT.\n\npackage messages\n\nimport \"golang.org/x/text/message\"\n")
+	})
+
+	Convey("errors", t, func() {
+		message, err := fmtstr.NewMessage("%[2]d items")
+		So(err, ShouldEqual, nil)
+		_, err = NewGoGenerator("messages").Generate(NewEntry("Items", "", message))
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		So(err.Error(), ShouldContainSubstring, "argument 1 is not used")
+
+		message, err = fmtstr.NewMessage("%d items")
+		So(err, ShouldEqual, nil)
+		_, err = NewGoGenerator("messages").Generate(NewEntry("Items", "", message), NewEntry("items", "", message))
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		So(err.Error(), ShouldContainSubstring, `duplicate name "Items"`)
+
+		_, err = NewGoGenerator("messages").Generate(&Entry{Name: "items", Message: message})
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		_, err = NewGoGenerator("messages").Generate(&Entry{Name: "Items"})
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		_, err = NewGoGenerator("my-messages").Generate()
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		_, err = NewGoGenerator("my-messages").GenerateTest()
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+
+		message, err = fmtstr.NewMessage("read: %w", ".Err")
+		So(err, ShouldEqual, nil)
+		_, err = NewGoGenerator("messages").Generate(NewEntry("Read", "", message))
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		So(err.Error(), ShouldEndWith, `Read: "%w" is not supported by message.Printer`)
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+// gTypeScriptReserved are the reserved words which cannot be used as the
+// names of the generated TypeScript functions
+var gTypeScriptReserved = map[string]struct{}{
+	"break": {}, "case": {}, "catch": {}, "class": {}, "const": {}, "continue": {},
+	"debugger": {}, "default": {}, "delete": {}, "do": {}, "else": {}, "enum": {},
+	"export": {}, "extends": {}, "false": {}, "finally": {}, "for": {}, "function": {},
+	"if": {}, "implements": {}, "import": {}, "in": {}, "instanceof": {}, "interface": {},
+	"let": {}, "new": {}, "null": {}, "package": {}, "private": {}, "protected": {},
+	"public": {}, "return": {}, "static": {}, "super": {}, "switch": {}, "this": {},
+	"throw": {}, "true": {}, "try": {}, "typeof": {}, "var": {}, "void": {},
+	"while": {}, "with": {}, "yield": {}, "await": {},
+}
+
+// TypeScriptGenerator generates TypeScript definitions for each Entry, ie:
+//
+//	export interface ItemsInCartArgs {
+//	  Count: number;
+//	  User: string;
+//	}
+//
+//	export const ItemsInCartKey = "%d items in %s's cart";
+//
+//	export function itemsInCart(args: ItemsInCartArgs): MessageRequest<ItemsInCartArgs> {
+//	  return { key: ItemsInCartKey, args };
+//	}
+//
+// The fields of the Args interfaces are the Variable labels, which are the
+// same names used by fmtstr.NewJSONSchema, with types derived from the
+// Variable Type: num and float are number, text is string, bool is boolean
+// and any is unknown, as are the %p, %x and %X verbs which accept several
+// kinds of values. The Key is the source Message Format, which is the
+// catalog key of the translations
+type TypeScriptGenerator struct{}
+
+// NewTypeScriptGenerator returns a new TypeScriptGenerator
+func NewTypeScriptGenerator() (g *TypeScriptGenerator) {
+	g = &TypeScriptGenerator{}
+	return
+}
+
+// Generate returns the TypeScript source code for the given entries, the
+// entries must have unique exported names and use all of their argument
+// positions
+func (g *TypeScriptGenerator) Generate(entries ...*Entry) (source []byte, err error) {
+	if err = validate(entries); err != nil {
+		return
+	}
+
+	var buf strings.Builder
+	buf.WriteString("// Code generated by fmtstr; DO NOT EDIT.\n\n")
+	buf.WriteString("/** MessageRequest is the message key and named arguments of a translation */\n")
+	buf.WriteString("export interface MessageRequest<T> {\n  key: string;\n  args: T;\n}\n")
+
+	for _, entry := range entries {
+		var params []*cParam
+		if params, err = entry.params(); err != nil {
+			return
+		}
+
+		args := entry.Name + "Args"
+		key := entry.Name + "Key"
+		name := strcase.ToLowerCamel(entry.Name)
+		if _, present := gTypeScriptReserved[name]; present {
+			name += "Message"
+		}
+
+		buf.WriteString("\n/**\n * " + args + " are the arguments of:\n *\n *     " + tsComment(entry.Message.Labelled) + "\n")
+		if entry.Comment != "" {
+			buf.WriteString(" *\n")
+			for _, line := range strings.Split(entry.Comment, "\n") {
+				buf.WriteString(strings.TrimRight(" * "+tsComment(line), " ") + "\n")
+			}
+		}
+		buf.WriteString(" */\n")
+
+		if len(params) == 0 {
+			buf.WriteString("export type " + args + " = Record<string, never>;\n")
+		} else {
+			buf.WriteString("export interface " + args + " {\n")
+			for _, param := range params {
+				buf.WriteString("  " + tsProperty(param.variable.Label) + ": " + tsType(param.variable) + ";\n")
+			}
+			buf.WriteString("}\n")
+		}
+
+		buf.WriteString("\nexport const " + key + " = " + tsString(entry.Message.Format) + ";\n\n")
+		if len(params) == 0 {
+			buf.WriteString("export function " + name + "(): MessageRequest<" + args + "> {\n")
+			buf.WriteString("  return { key: " + key + ", args: {} };\n}\n")
+		} else {
+			buf.WriteString("export function " + name + "(args: " + args + "): MessageRequest<" + args + "> {\n")
+			buf.WriteString("  return { key: " + key + ", args };\n}\n")
+		}
+	}
+
+	source = []byte(buf.String())
+	return
+}
+
+// tsType returns the TypeScript type of the Variable given, derived from its
+// Type except for the %p, %x and %X verbs of num Variables which are unknown
+func tsType(variable *fmtstr.Variable) (name string) {
+	switch variable.Type {
+	case "num":
+		switch variable.Verb {
+		case "p", "x", "X":
+			name = "unknown"
+		default:
+			name = "number"
+		}
+	case "float":
+		name = "number"
+	case "text":
+		name = "string"
+	case "bool":
+		name = "boolean"
+	default:
+		name = "unknown"
+	}
+	return
+}
+
+// tsString returns the TypeScript string literal of the value given
+func tsString(value string) (literal string) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	// U+2028 and U+2029 are escaped by encoding/json
+	literal = strings.TrimSuffix(buf.String(), "\n")
+	return
+}
+
+// tsProperty returns the property name given, quoted unless it is a valid
+// identifier
+func tsProperty(name string) (property string) {
+	for idx, r := range name {
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (idx > 0 && unicode.IsDigit(r))) {
+			return tsString(name)
+		}
+	}
+	if property = name; property == "" {
+		property = `""`
+	}
+	return
+}
+
+// tsComment returns the text with any line breaks and comment terminators
+// escaped, for use within a single line of a block comment
+func tsComment(text string) (comment string) {
+	comment = strings.NewReplacer("\r", `\r`, "\n", `\n`, "*/", `*\/`).Replace(text)
+	return
+}
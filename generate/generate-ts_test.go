@@ -0,0 +1,78 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func TestTypeScriptGenerator(t *testing.T) {
+	Convey("Generate", t, func() {
+		source, err := NewTypeScriptGenerator().Generate(testEntries()...)
+		So(err, ShouldEqual, nil)
+
+		golden := "testdata/messages.ts"
+		if *gUpdate {
+			So(os.WriteFile(golden, source, 0644), ShouldEqual, nil)
+		}
+		expected, err := os.ReadFile(golden)
+		So(err, ShouldEqual, nil)
+		So(string(source), ShouldEqual, string(expected))
+	})
+
+	Convey("reserved and quoted names", t, func() {
+		message, err := fmtstr.NewMessage("%s", ".Name")
+		So(err, ShouldEqual, nil)
+		message.Variables[0].Label = "first-name"
+		source, err := NewTypeScriptGenerator().Generate(NewEntry("New", "no */ here", message))
+		So(err, ShouldEqual, nil)
+		So(string(source), ShouldContainSubstring, " * no *\\/ here\n")
+		So(string(source), ShouldContainSubstring, "  \"first-name\": string;\n")
+		So(string(source), ShouldContainSubstring, "export function newMessage(args: NewArgs): MessageRequest<NewArgs> {\n")
+	})
+
+	Convey("errors", t, func() {
+		message, err := fmtstr.NewMessage("%[2]d items")
+		So(err, ShouldEqual, nil)
+		_, err = NewTypeScriptGenerator().Generate(NewEntry("Items", "", message))
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+		_, err = NewTypeScriptGenerator().Generate(&Entry{Name: "Items"})
+		So(errors.Is(err, ErrInvalidEntry), ShouldBeTrue)
+	})
+
+	Convey("helpers", t, func() {
+		So(tsType(&fmtstr.Variable{Type: "num", Verb: "d"}), ShouldEqual, "number")
+		So(tsType(&fmtstr.Variable{Type: "float", Verb: "f"}), ShouldEqual, "number")
+		So(tsType(&fmtstr.Variable{Type: "text", Verb: "s"}), ShouldEqual, "string")
+		So(tsType(&fmtstr.Variable{Type: "bool", Verb: "t"}), ShouldEqual, "boolean")
+		So(tsType(&fmtstr.Variable{Type: "any", Verb: "v"}), ShouldEqual, "unknown")
+		So(tsType(&fmtstr.Variable{Type: "num", Verb: "p"}), ShouldEqual, "unknown")
+		So(tsType(&fmtstr.Variable{Type: "num", Verb: "x"}), ShouldEqual, "unknown")
+		So(tsType(&fmtstr.Variable{Type: "float", Verb: "X"}), ShouldEqual, "number")
+		// the Variable Type takes precedence over the Verb.Type
+		So(tsType(&fmtstr.Variable{Type: "text", Verb: "v"}), ShouldEqual, "string")
+		So(tsString("a \"b\" <c>\n\u2028"), ShouldEqual, `"a \"b\" <c>\n\u2028"`)
+		So(tsProperty("Count"), ShouldEqual, "Count")
+		So(tsProperty("$_a1"), ShouldEqual, "$_a1")
+		So(tsProperty("1a"), ShouldEqual, `"1a"`)
+		So(tsProperty(""), ShouldEqual, `""`)
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generate provides code generators for typed message functions,
+// where each of the decomposed source Message Variables is a typed
+// parameter named by its Label, so that the arguments of translated messages
+// are checked at compile time
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+var (
+	ErrInvalidEntry = errors.New("invalid entry")
+)
+
+// gMaxNameWords is the maximum number of words used by EntryName
+const gMaxNameWords = 6
+
+// Entry is a single named source Message to generate code for
+type Entry struct {
+	// Name is the exported name of the generated function, ie: ItemsInCart
+	Name string
+	// Comment is the optional description of the message
+	Comment string
+	// Message is the decomposed source format string
+	Message *fmtstr.Message
+}
+
+// NewEntry returns a new Entry for the given name and source Message, the
+// name is converted to CamelCase and when empty, the EntryName of the
+// Message is used
+func NewEntry(name, comment string, message *fmtstr.Message) (entry *Entry) {
+	if name = strcase.ToCamel(name); name == "" {
+		name = EntryName(message)
+	}
+	entry = &Entry{
+		Name:    name,
+		Comment: comment,
+		Message: message,
+	}
+	return
+}
+
+// EntryName returns a CamelCase name derived from the first few words of the
+// labelled form of the Message, ie: `{Count} items in {User}'s cart` is
+// named CountItemsInUsersCart
+func EntryName(message *fmtstr.Message) (name string) {
+	var words []string
+	labelled := strings.NewReplacer("{", "", "}", "").Replace(message.Labelled)
+	for _, word := range strings.FieldsFunc(labelled, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		if word = strings.ReplaceAll(word, "'", ""); word != "" {
+			words = append(words, word)
+		}
+		if len(words) == gMaxNameWords {
+			break
+		}
+	}
+	if name = strcase.ToCamel(strings.Join(words, " ")); name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Message" + name
+	}
+	return
+}
+
+// cParam is a generated function parameter
+type cParam struct {
+	name     string
+	variable *fmtstr.Variable
+}
+
+// validate checks that the entries have valid and unique names
+func validate(entries []*Entry) (err error) {
+	names := make(map[string]struct{})
+	for _, entry := range entries {
+		if entry.Message == nil {
+			err = fmt.Errorf("%w: %s: missing message", ErrInvalidEntry, entry.Name)
+			return
+		} else if !token.IsIdentifier(entry.Name) || !token.IsExported(entry.Name) {
+			err = fmt.Errorf("%w: %q is not an exported identifier", ErrInvalidEntry, entry.Name)
+			return
+		} else if _, present := names[entry.Name]; present {
+			err = fmt.Errorf("%w: duplicate name %q", ErrInvalidEntry, entry.Name)
+			return
+		}
+		names[entry.Name] = struct{}{}
+	}
+	return
+}
+
+// params returns the parameters of the Entry in argument position order,
+// named with the lowerCamelCase Variable labels. Names which are in the
+// reserved list are suffixed with "Arg" and all argument positions must be
+// used by the format string
+func (e *Entry) params(reserved ...string) (params []*cParam, err error) {
+	taken := make(map[string]struct{})
+	for _, name := range reserved {
+		taken[name] = struct{}{}
+	}
+
+	for idx, variable := range e.Message.Variables.Sort() {
+		if variable.Pos != idx+1 {
+			err = fmt.Errorf("%w: %s: argument %d is not used by the format %q", ErrInvalidEntry, e.Name, idx+1, e.Message.Format)
+			return
+		}
+		name := strcase.ToLowerCamel(variable.Label)
+		if name == "" {
+			name = strcase.ToLowerCamel(variable.Key())
+		}
+		for base, count := name, 1; ; count++ {
+			if _, present := taken[name]; !present {
+				break
+			}
+			if count == 1 {
+				name = base + "Arg"
+			} else {
+				name = fmt.Sprintf("%sArg%d", base, count)
+			}
+		}
+		taken[name] = struct{}{}
+		params = append(params, &cParam{name: name, variable: variable})
+	}
+	return
+}
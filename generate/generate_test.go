@@ -0,0 +1,55 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func TestEntry(t *testing.T) {
+	Convey("EntryName", t, func() {
+		for _, test := range []struct {
+			format string
+			argv   []string
+			name   string
+		}{
+			{"%d items in %s's cart", []string{".Count", ".User"}, "CountItemsInUsersCart"},
+			{"one two three four five six seven", nil, "OneTwoThreeFourFiveSix"},
+			{"42 is the answer", nil, "Message42IsTheAnswer"},
+			{"!!!", nil, "Message"},
+			{"%d", nil, "Num"},
+		} {
+			message, err := fmtstr.NewMessage(test.format, test.argv...)
+			So(err, ShouldEqual, nil)
+			So(EntryName(message), ShouldEqual, test.name)
+		}
+	})
+
+	Convey("params", t, func() {
+		message, err := fmtstr.NewMessage("%s %s %d %v %s", ".P", ".Name", ".Type", ".Count", ".Count")
+		So(err, ShouldEqual, nil)
+		params, err := NewEntry("Test", "", message).params("p", "pArg", "type")
+		So(err, ShouldEqual, nil)
+		var names []string
+		for _, param := range params {
+			names = append(names, param.name)
+		}
+		So(names, ShouldResemble, []string{"pArg2", "name", "typeArg", "count", "count1"})
+	})
+}
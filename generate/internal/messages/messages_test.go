@@ -0,0 +1,51 @@
+// Copyright (c) 2024  The Go-Curses Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/go-corelibs/fmtstr"
+)
+
+func TestMessages(t *testing.T) {
+	en := message.NewPrinter(language.English)
+
+	Convey("each generated function formats its source message", t, func() {
+		So(ItemsInCart(en, 3, "Alice"), ShouldEqual, fmt.Sprintf("%[2]s has %[1]d items in the cart", 3, "Alice"))
+		So(CountItemsInUsersCart(en, 3, "Alice"), ShouldEqual, fmt.Sprintf("%d items in %s's cart", 3, "Alice"))
+		So(OrderTotal(en, 12.5, true), ShouldEqual, fmt.Sprintf("Total: %8.2f (paid: %t)", 12.5, true))
+		So(Welcome(en, "Bob"), ShouldEqual, fmt.Sprintf("Welcome back, %q!", "Bob"))
+		So(NoArgs(en), ShouldEqual, "Nothing to see here")
+		So(Mixed(en, []int{1}, 255, "x"), ShouldEqual, fmt.Sprintf("%v and %x and %s", []int{1}, 255, "x"))
+		So(Mixed(en, nil, "hex", "x"), ShouldEqual, "<nil> and 686578 and x")
+		addr := new(int)
+		So(Stored(en, "it", addr), ShouldEqual, fmt.Sprintf("%s stored at %p", "it", addr))
+	})
+
+	Convey("the source message is the catalog key", t, func() {
+		source, err := fmtstr.NewMessage("%[2]s has %[1]d items in the cart", ".Count", ".User")
+		So(err, ShouldEqual, nil)
+		builder := fmtstr.NewCatalogBuilder()
+		So(builder.SetLabelled(language.German, source, "{User} hat {Count} Artikel im Warenkorb"), ShouldEqual, nil)
+		de := message.NewPrinter(language.German, message.Catalog(builder.Builder))
+		So(ItemsInCart(de, 3, "Alice"), ShouldEqual, "Alice hat 3 Artikel im Warenkorb")
+	})
+}
@@ -1,11 +1,13 @@
 module github.com/go-corelibs/fmtstr
 
-go 1.21.5
+go 1.22.0
 
 require (
 	github.com/go-corelibs/strings v1.1.1
 	github.com/iancoleman/strcase v0.3.0
 	github.com/smartystreets/goconvey v1.8.1
+	golang.org/x/text v0.19.0
+	golang.org/x/tools v0.26.0
 )
 
 require (
@@ -17,7 +19,8 @@ require (
 	github.com/sirupsen/logrus v1.8.1 // indirect
 	github.com/smarty/assertions v1.15.0 // indirect
 	github.com/weppos/publicsuffix-go v0.30.1 // indirect
-	golang.org/x/net v0.12.0 // indirect
-	golang.org/x/sys v0.10.0 // indirect
-	golang.org/x/text v0.11.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/net v0.30.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
 )